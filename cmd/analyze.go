@@ -0,0 +1,97 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"CardGame3V3Go/pkg"
+)
+
+// runAnalyze reviews the decisions recorded in a replay file and reports
+// the turns where the search found a clearly better shot.
+func runAnalyze(args []string) {
+	fs := flag.NewFlagSet("analyze", flag.ExitOnError)
+	hand := fs.Int("hand", 0, "hand of the file to review, from 1, 0 for all")
+	seats := fs.String("seats", "", "comma separated seats to review, the human seats if empty")
+	samples := fs.Int("samples", 60, "search iterations per shot")
+	threshold := fs.Float64("threshold", 0.15, "least loss of win rate reported as a mistake")
+	asJSON := fs.Bool("json", false, "print the analysis as JSON")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "usage: analyze [flags] file")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+	if fs.NArg() != 1 {
+		fs.Usage()
+		os.Exit(2)
+	}
+	a := pkg.NewAnalyzer()
+	a.Search.Iterations = *samples
+	a.Threshold = *threshold
+	if *seats != "" {
+		a.Seats = []int{}
+		for _, s := range strings.Split(*seats, ",") {
+			seat, err := strconv.Atoi(strings.TrimSpace(s))
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "bad seat %q\n", s)
+				os.Exit(2)
+			}
+			a.Seats = append(a.Seats, seat)
+		}
+	}
+	f, err := os.Open(fs.Arg(0))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	replays, err := pkg.ReadReplays(f)
+	f.Close()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	if *hand < 0 || *hand > len(replays) {
+		fmt.Fprintf(os.Stderr, "hand %d not in replay of %d hands\n", *hand, len(replays))
+		os.Exit(2)
+	}
+	if *hand != 0 {
+		replays = replays[*hand-1 : *hand]
+	}
+	var analyses []pkg.Analysis
+	for i := range replays {
+		analysis, err := a.Analyze(&replays[i])
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "bad replay: %v\n", err)
+			os.Exit(1)
+		}
+		analyses = append(analyses, analysis)
+	}
+	if *asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		enc.Encode(analyses)
+		return
+	}
+	for i, analysis := range analyses {
+		if len(analyses) > 1 {
+			fmt.Printf("========== hand %d ==========\n", i+1)
+		}
+		if len(analysis.Seats) == 0 {
+			fmt.Println("no seats to review, pick some with -seats")
+			continue
+		}
+		for _, m := range analysis.Mistakes {
+			fmt.Printf("turn %d Player%d played %s (wins %.0f%%), %s was better (wins %.0f%%), loss %.0f%%\n",
+				m.Turn, m.Seat, m.Played, 100*m.PlayedWin, m.Better, 100*m.BetterWin, 100*m.Loss)
+		}
+		var names []string
+		for _, seat := range analysis.Seats {
+			names = append(names, fmt.Sprintf("Player%d (%s)", seat, analysis.Names[seat]))
+		}
+		fmt.Printf("%d mistakes in %d decisions of %s\n", len(analysis.Mistakes), analysis.Decisions, strings.Join(names, ", "))
+	}
+}
@@ -0,0 +1,159 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"net"
+	"os"
+	"strings"
+
+	"CardGame3V3Go/pkg"
+)
+
+// runClient joins a table served by the server command and plays the seat
+// from the terminal, see pkg.Message for the protocol.
+func runClient(args []string) {
+	fs := flag.NewFlagSet("client", flag.ExitOnError)
+	addr := fs.String("addr", "localhost:7777", "address of the server")
+	name := fs.String("name", os.Getenv("USER"), "name shown to the other players")
+	seat := fs.Int("seat", -1, "seat to take, -1 for any")
+	team := fs.Uint("team", 0, "team to join, 0 for any")
+	fs.Parse(args)
+
+	conn, err := net.Dial("tcp", *addr)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	defer conn.Close()
+	mc := pkg.NewMessageConn(conn)
+	join := pkg.Join{
+		Name: *name,
+		Team: uint32(*team),
+	}
+	if *seat >= 0 {
+		join.Seat = seat
+	}
+	if err := mc.Send(pkg.MsgJoin, join); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	msgs := make(chan pkg.Message)
+	go func() {
+		defer close(msgs)
+		for {
+			msg, err := mc.Receive()
+			if err != nil {
+				return
+			}
+			msgs <- msg
+		}
+	}()
+	lines := make(chan string)
+	go func() {
+		scanner := bufio.NewScanner(os.Stdin)
+		for scanner.Scan() {
+			lines <- strings.TrimSpace(scanner.Text())
+		}
+		close(lines)
+	}()
+
+	rules := pkg.DefaultRules()
+	for {
+		select {
+		case msg, ok := <-msgs:
+			if !ok {
+				fmt.Println("connection closed")
+				return
+			}
+			if over := printMessage(msg, &rules); over {
+				return
+			}
+		case line, ok := <-lines:
+			if !ok {
+				return
+			}
+			if err := sendLine(mc, line); err != nil {
+				fmt.Printf("Oops, %v! Please try again:\n", err)
+			}
+		}
+	}
+}
+
+// sendLine sends start, pass or the typed cards.
+func sendLine(mc *pkg.MessageConn, line string) error {
+	switch {
+	case line == "":
+		return nil
+	case strings.EqualFold(line, "start"):
+		return mc.Send(pkg.MsgStart, nil)
+	case strings.HasPrefix("pass", strings.ToLower(line)):
+		return mc.Send(pkg.MsgPass, nil)
+	}
+	cards, err := pkg.ParseCards(line)
+	if err != nil {
+		return err
+	}
+	return mc.Send(pkg.MsgPlay, pkg.Play{Cards: cards})
+}
+
+// printMessage shows a message of the server and reports whether the match
+// is over. Rules are those of the last deal.
+func printMessage(msg pkg.Message, rules *pkg.Rules) (over bool) {
+	var err error
+	switch msg.Type {
+	case pkg.MsgSeated:
+		var seated pkg.Seated
+		err = msg.Decode(&seated)
+		fmt.Printf("You are Player%d of team %d, type start to fill the empty seats with AI\n", seated.Seat, seated.Team)
+	case pkg.MsgDeal:
+		var deal pkg.Deal
+		err = msg.Decode(&deal)
+		*rules = deal.Rules
+		fmt.Println("========== new hand ==========")
+		for i, name := range deal.Names {
+			fmt.Printf("Player%d: %s, team %d\n", i, name, deal.Teams[i])
+		}
+		fmt.Printf("Your cards: %s\nPlayer%d leads\n", deal.Hand, deal.Leader)
+	case pkg.MsgTurn:
+		var turn pkg.Turn
+		err = msg.Decode(&turn)
+		fmt.Printf("Current cards: %s, len=%d\n", turn.Hand, len(turn.Hand))
+		fmt.Printf("Cards left: %v\n", turn.CardCounts)
+		fmt.Printf("Please type your next shot on %s, friend=%v: \n", turn.Shot, turn.Friend)
+	case pkg.MsgPlayed:
+		var played pkg.Played
+		err = msg.Decode(&played)
+		fmt.Printf("Player%d: %s\n", played.Seat, played.Shot)
+	case pkg.MsgPassed:
+		var passed pkg.PassedMsg
+		err = msg.Decode(&passed)
+		fmt.Printf("Player%d: pass\n", passed.Seat)
+	case pkg.MsgRoundReset:
+		var round pkg.RoundResetMsg
+		err = msg.Decode(&round)
+		fmt.Printf("========== Player%d leads ==========\n", round.Leader)
+	case pkg.MsgFinished:
+		var finished pkg.FinishedMsg
+		err = msg.Decode(&finished)
+		fmt.Printf("Player%d finishes at place %d\n", finished.Seat, finished.Place)
+	case pkg.MsgHandOver:
+		var hand pkg.HandOverMsg
+		err = msg.Decode(&hand)
+		fmt.Printf("Team %d wins the hand for %d points, %v\n", hand.Result.Winner, hand.Points, hand.Result.Ranking)
+		fmt.Println(scoreLine(rules, hand.Scores))
+		over = hand.Over
+	case pkg.MsgError:
+		var e pkg.ErrorMsg
+		err = msg.Decode(&e)
+		fmt.Printf("Oops, %s! Please try again:\n", e.Error)
+	default:
+		fmt.Printf("unknown message %q\n", msg.Type)
+	}
+	if err != nil {
+		fmt.Printf("bad %s message: %v\n", msg.Type, err)
+	}
+	return
+}
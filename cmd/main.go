@@ -1,8 +1,222 @@
 package main
 
-import "CardGame3V3Go/pkg"
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"CardGame3V3Go/pkg"
+	"CardGame3V3Go/pkg/tui"
+)
 
 func main() {
-	g := pkg.NewGame()
-	g.Start()
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "replay":
+			runReplay(os.Args[2:])
+			return
+		case "server":
+			runServer(os.Args[2:])
+			return
+		case "client":
+			runClient(os.Args[2:])
+			return
+		case "simulate":
+			runSimulate(os.Args[2:])
+			return
+		case "ratings":
+			runRatings(os.Args[2:])
+			return
+		case "tournament":
+			runTournament(os.Args[2:])
+			return
+		case "analyze":
+			runAnalyze(os.Args[2:])
+			return
+		}
+	}
+	seed := flag.Int64("seed", 0, "random seed for dealing and seating, 0 for a time-based seed")
+	seats := flag.String("seats", "human,normal,normal,normal,normal,normal",
+		"comma separated strategies of the six seats, one of "+strings.Join(pkg.StrategyNames(), ", "))
+	rulesPath := flag.String("rules", "", "JSON or YAML file with house rules, the default rules if empty")
+	target := flag.Int("target", 0, "play hands until a team scores this many points, 0 for a single hand")
+	record := flag.String("record", "", "write a replay of the game to this file")
+	hints := flag.Bool("hints", true, "let human seats ask for hints, turn off for competitive play")
+	ratingsPath := flag.String("ratings", "", "update the ratings in this file with the seats' strategy names, see the ratings command")
+	flag.Parse()
+	rules := loadRules(*rulesPath)
+	m := pkg.NewMatch(pkg.GameOptions{Seed: *seed, Rules: &rules}, *target)
+	g := &m.Game
+	names, err := setStrategies(g, *seats)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	fmt.Printf("seed=%d\n", g.Seed)
+	if *record != "" {
+		f, err := os.Create(*record)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(2)
+		}
+		defer f.Close()
+		recorder := pkg.RecordReplay(g, f, names)
+		defer func() {
+			if err := recorder.Err(); err != nil {
+				fmt.Fprintln(os.Stderr, err)
+			}
+		}()
+	}
+	var ratings *pkg.Ratings
+	if *ratingsPath != "" {
+		ratings = loadRatings(*ratingsPath)
+		defer saveRatings(*ratingsPath, ratings)
+	}
+	for i := range g.Players {
+		if h, ok := g.Players[i].Strategy.(*pkg.HumanStrategy); ok {
+			h.Hints = *hints
+		}
+		if o, ok := g.Players[i].Strategy.(pkg.Observer); ok {
+			g.Subscribe(o)
+		}
+	}
+	if strings.Contains(*seats, "tui") {
+		restore, err := rawTerminal()
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(2)
+		}
+		defer restore()
+	} else {
+		g.Subscribe(pkg.ObserverFunc(func(e pkg.Event) {
+			printEvent(g, e)
+		}))
+	}
+	if *target == 0 {
+		g.Start()
+		if err := playHand(g); err != nil {
+			fmt.Println(err)
+			return
+		}
+		result, err := g.Result()
+		if err != nil {
+			panic(err)
+		}
+		if ratings != nil {
+			ratings.Record(names, g.Rules.Teams, result)
+		}
+		fmt.Printf("Team %d wins after %d turns!\n", result.Winner, result.Turns)
+		for place, seat := range result.Ranking {
+			fmt.Printf("%d. Player%d %s\n", place+1, seat, result.Remaining[seat])
+		}
+		return
+	}
+	for m.Winner() == 0 {
+		fmt.Printf("========== hand %d ==========\n", len(m.Hands)+1)
+		for _, t := range m.StartHand() {
+			fmt.Printf("Player%d pays %s to Player%d, gets %s back\n", t.From, t.Card, t.To, t.Return)
+		}
+		if err := playHand(g); err != nil {
+			fmt.Println(err)
+			return
+		}
+		hand, err := m.FinishHand()
+		if err != nil {
+			panic(err)
+		}
+		if ratings != nil {
+			ratings.Record(names, g.Rules.Teams, hand.HandResult)
+		}
+		fmt.Printf("Team %d wins the hand for %d points, %v\n", hand.Winner, hand.Points, hand.Ranking)
+		fmt.Println(scoreLine(&g.Rules, m.Scores))
+	}
+	fmt.Printf("Team %d wins the match!\n", m.Winner())
+}
+
+// scoreLine shows the scores of the teams of rules.
+func scoreLine(rules *pkg.Rules, scores map[uint32]int) string {
+	var teams []string
+	for _, team := range rules.TeamNumbers() {
+		teams = append(teams, fmt.Sprintf("Team %d %d", team, scores[team]))
+	}
+	return "Score: " + strings.Join(teams, ", ")
+}
+
+// playHand plays the hand out, or until the input of a human seat ends.
+func playHand(g *pkg.Game) error {
+	for !g.IsFinished() {
+		curPlayer := g.CurrentPlayer()
+		shot := g.Players[curPlayer].NextShot(g.View(curPlayer), g.CurrentShot())
+		if h, ok := g.Players[curPlayer].Strategy.(*pkg.HumanStrategy); ok && h.Err != nil {
+			return fmt.Errorf("Player%d left the game: %v", curPlayer, h.Err)
+		}
+		if err := g.Apply(curPlayer, shot); err != nil {
+			panic(err)
+		}
+	}
+	return nil
+}
+
+func printEvent(g *pkg.Game, e pkg.Event) {
+	switch e := e.(type) {
+	case pkg.HandDealt, pkg.RoundReset:
+		showCards(g)
+	case pkg.ShotPlayed:
+		fmt.Printf("Player%d: %s\n", e.Seat, e.Shot)
+	case pkg.Passed:
+		fmt.Printf("Player%d: pass\n", e.Seat)
+	case pkg.PlayerFinished:
+		fmt.Printf("Player%d finishes\n", e.Seat)
+	}
+}
+
+func loadRules(path string) pkg.Rules {
+	if path == "" {
+		return pkg.DefaultRules()
+	}
+	rules, err := pkg.LoadRules(path)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	return rules
+}
+
+// stdin is shared by all seats reading the terminal, so that none of them
+// buffers input typed for another.
+var stdin = bufio.NewReader(os.Stdin)
+
+func init() {
+	pkg.RegisterStrategy("human", func() pkg.Strategy {
+		return pkg.NewHumanStrategy(stdin, os.Stdout)
+	})
+	pkg.RegisterStrategy("tui", func() pkg.Strategy {
+		return tui.NewStrategy(stdin, os.Stdout)
+	})
+}
+
+func setStrategies(g *pkg.Game, seats string) (names [6]string, err error) {
+	list := strings.Split(seats, ",")
+	if len(list) != len(g.Players) {
+		return names, fmt.Errorf("expect %d strategies, got %q", len(g.Players), seats)
+	}
+	for i, name := range list {
+		names[i] = strings.TrimSpace(name)
+		if g.Players[i].Strategy, err = pkg.NewStrategy(names[i]); err != nil {
+			return
+		}
+	}
+	return
+}
+
+func showCards(g *pkg.Game) {
+	fmt.Println("========== all cards ==========")
+	for i := 0; i < len(g.Players); i++ {
+		if p := &g.Players[i]; !p.IsFinished() {
+			fmt.Printf("Player%d: %s, len=%d\n", i, pkg.Cards(p.Cards), len(p.Cards))
+		}
+	}
+	fmt.Println("===============================")
 }
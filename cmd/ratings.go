@@ -0,0 +1,76 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"CardGame3V3Go/pkg"
+)
+
+// runRatings adds the hands of replay files to the ratings and prints the
+// leaderboard.
+func runRatings(args []string) {
+	fs := flag.NewFlagSet("ratings", flag.ExitOnError)
+	path := fs.String("file", "ratings.json", "file with the ratings")
+	top := fs.Int("top", 0, "show only this many players, 0 for all")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "usage: ratings [flags] [replay files to rate]")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+	ratings := loadRatings(*path)
+	if fs.NArg() != 0 {
+		for _, name := range fs.Args() {
+			f, err := os.Open(name)
+			if err != nil {
+				fmt.Fprintln(os.Stderr, err)
+				os.Exit(2)
+			}
+			replays, err := pkg.ReadReplays(f)
+			f.Close()
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "%s: %v\n", name, err)
+				os.Exit(2)
+			}
+			for i := range replays {
+				r := &replays[i]
+				g, err := r.Game(-1)
+				if err == nil {
+					var result pkg.HandResult
+					if result, err = g.Result(); err == nil {
+						ratings.Record(r.Seats, r.Rules.Teams, result)
+					}
+				}
+				if err != nil {
+					fmt.Fprintf(os.Stderr, "%s: hand %d: %v\n", name, i+1, err)
+				}
+			}
+		}
+		saveRatings(*path, ratings)
+	}
+	board := ratings.Leaderboard()
+	if *top > 0 && *top < len(board) {
+		board = board[:*top]
+	}
+	fmt.Printf("%4s %-16s %7s %6s %6s\n", "#", "player", "rating", "hands", "won")
+	for i, p := range board {
+		fmt.Printf("%4d %-16s %7.1f %6d %5.1f%%\n", i+1, p.Name, p.Rating, p.Hands, 100*float64(p.Wins)/float64(p.Hands))
+	}
+}
+
+func loadRatings(path string) *pkg.Ratings {
+	ratings, err := pkg.LoadRatings(path)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	return ratings
+}
+
+func saveRatings(path string, ratings *pkg.Ratings) {
+	if err := ratings.Save(path); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
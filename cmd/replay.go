@@ -0,0 +1,62 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"CardGame3V3Go/pkg"
+)
+
+// runReplay steps through a replay file written with -record, checking
+// every move, and shows the hands at the requested turn.
+func runReplay(args []string) {
+	fs := flag.NewFlagSet("replay", flag.ExitOnError)
+	hand := fs.Int("hand", 1, "hand of the file to replay, from 1")
+	turn := fs.Int("turn", -1, "show the hands after this turn, -1 for the end of the hand")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "usage: replay [flags] file")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+	if fs.NArg() != 1 {
+		fs.Usage()
+		os.Exit(2)
+	}
+	f, err := os.Open(fs.Arg(0))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	replays, err := pkg.ReadReplays(f)
+	f.Close()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	if *hand < 1 || *hand > len(replays) {
+		fmt.Fprintf(os.Stderr, "hand %d not in replay of %d hands\n", *hand, len(replays))
+		os.Exit(2)
+	}
+	r := &replays[*hand-1]
+	if _, err := r.Game(-1); err != nil {
+		fmt.Fprintf(os.Stderr, "bad replay: %v\n", err)
+		os.Exit(1)
+	}
+	if *turn < 0 || *turn > len(r.Moves) {
+		*turn = len(r.Moves)
+	}
+	fmt.Printf("hand %d of %d, seed=%d, seats=%v, Player%d leads\n",
+		*hand, len(replays), r.Seed, r.Seats, r.Leader)
+	for _, move := range r.Moves[:*turn] {
+		fmt.Printf("%4d Player%d: %s\n", move.Turn, move.Seat, move.Shot())
+	}
+	g, _ := r.Game(*turn)
+	fmt.Printf("========== turn %d ==========\n", *turn)
+	for i := range g.Players {
+		fmt.Printf("Player%d: %s, len=%d\n", i, pkg.Cards(g.Players[i].Cards), len(g.Players[i].Cards))
+	}
+	if result, err := g.Result(); err == nil {
+		fmt.Printf("Team %d wins after %d turns\n", result.Winner, result.Turns)
+	}
+}
@@ -0,0 +1,43 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"CardGame3V3Go/pkg"
+	"CardGame3V3Go/pkg/server"
+)
+
+// runServer hosts a table for remote players, see package server.
+func runServer(args []string) {
+	fs := flag.NewFlagSet("server", flag.ExitOnError)
+	addr := fs.String("addr", ":7777", "TCP address to listen on")
+	seed := fs.Int64("seed", 0, "random seed for dealing and seating, 0 for a time-based seed")
+	rulesPath := fs.String("rules", "", "JSON or YAML file with house rules, the default rules if empty")
+	target := fs.Int("target", 0, "play hands until a team scores this many points, 0 for a single hand")
+	ai := fs.String("ai", "normal", "strategy of the seats nobody joined")
+	ratingsPath := fs.String("ratings", "", "update the ratings in this file after the match, see the ratings command")
+	turnTime := fs.Duration("turn-time", 2*time.Minute, "time a client has for its shot before the AI takes its seat, 0 for no limit on shots")
+	fs.Parse(args)
+	rules := loadRules(*rulesPath)
+	if _, err := pkg.NewStrategy(*ai); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	s := server.New(pkg.GameOptions{Seed: *seed, Rules: &rules}, *target)
+	s.AI = *ai
+	s.TurnTime = *turnTime
+	if *ratingsPath != "" {
+		s.Ratings = loadRatings(*ratingsPath)
+	}
+	fmt.Printf("listening on %s\n", *addr)
+	if err := s.ListenAndServe(*addr); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	if *ratingsPath != "" {
+		saveRatings(*ratingsPath, s.Ratings)
+	}
+}
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"runtime"
+	"strings"
+	"time"
+
+	"CardGame3V3Go/pkg"
+)
+
+// runSimulate plays many hands between AI strategies and reports how every
+// team and seat did.
+func runSimulate(args []string) {
+	fs := flag.NewFlagSet("simulate", flag.ExitOnError)
+	games := fs.Int("games", 100, "number of hands to play")
+	seed := fs.Int64("seed", 0, "random seed of the first hand, 0 for a time-based seed")
+	seats := fs.String("seats", "normal,normal,normal,normal,normal,normal",
+		"comma separated strategies of the six seats, one of "+strings.Join(pkg.StrategyNames(), ", "))
+	rulesPath := fs.String("rules", "", "JSON or YAML file with house rules, the default rules if empty")
+	workers := fs.Int("workers", runtime.NumCPU(), "number of hands played at once")
+	asJSON := fs.Bool("json", false, "print the result as JSON")
+	ratingsPath := fs.String("ratings", "", "update the ratings in this file with the hands, see the ratings command")
+	fs.Parse(args)
+	rules := loadRules(*rulesPath)
+	opts := pkg.SimOptions{
+		Games:   *games,
+		Seed:    *seed,
+		Rules:   &rules,
+		Workers: *workers,
+	}
+	list := strings.Split(*seats, ",")
+	if len(list) != len(opts.Seats) {
+		fmt.Fprintf(os.Stderr, "expect %d strategies, got %q\n", len(opts.Seats), *seats)
+		os.Exit(2)
+	}
+	for i, name := range list {
+		if name = strings.TrimSpace(name); name == "human" || name == "tui" {
+			fmt.Fprintf(os.Stderr, "seat %d: %s needs a player, simulate AI strategies only\n", i, name)
+			os.Exit(2)
+		}
+		opts.Seats[i] = strings.TrimSpace(name)
+	}
+	start := time.Now()
+	result, err := pkg.Simulate(opts)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	if *ratingsPath != "" {
+		ratings := loadRatings(*ratingsPath)
+		for _, hand := range result.Hands {
+			ratings.Record(result.Seats, result.Teams, hand)
+		}
+		saveRatings(*ratingsPath, ratings)
+	}
+	if *asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		enc.Encode(result)
+		return
+	}
+	fmt.Printf("%d hands from seed %d in %v, 95%% confidence intervals\n",
+		result.Games, result.Seed, time.Since(start).Round(time.Millisecond))
+	for _, team := range rules.TeamNumbers() {
+		var names []string
+		for seat, t := range result.Teams {
+			if t == team {
+				names = append(names, fmt.Sprintf("%d:%s", seat, result.Seats[seat]))
+			}
+		}
+		fmt.Printf("Team %d win rate %s (%s)\n", team, result.WinRate[team], strings.Join(names, " "))
+	}
+	for seat, place := range result.Place {
+		fmt.Printf("Player%d %-8s average place %s\n", seat, result.Seats[seat], place)
+	}
+	fmt.Printf("turns per hand %s\n", result.Turns)
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"runtime"
+	"strings"
+	"time"
+
+	"CardGame3V3Go/pkg"
+)
+
+// runTournament plays a round robin between AI teams and prints the
+// standings and the result of every pairing.
+func runTournament(args []string) {
+	fs := flag.NewFlagSet("tournament", flag.ExitOnError)
+	entries := fs.String("entries", "normal,coop,planner",
+		"comma separated teams, a strategy or three joined by +, strategies are "+strings.Join(pkg.StrategyNames(), ", "))
+	deals := fs.Int("deals", 20, "deals per pairing, each played with both seatings")
+	seed := fs.Int64("seed", 0, "random seed of the first deal, 0 for a time-based seed")
+	rulesPath := fs.String("rules", "", "JSON or YAML file with house rules, the default rules if empty")
+	workers := fs.Int("workers", runtime.NumCPU(), "number of hands played at once")
+	asJSON := fs.Bool("json", false, "print the result as JSON")
+	fs.Parse(args)
+	rules := loadRules(*rulesPath)
+	opts := pkg.TournamentOptions{
+		Deals:   *deals,
+		Seed:    *seed,
+		Rules:   &rules,
+		Workers: *workers,
+	}
+	for _, entry := range strings.Split(*entries, ",") {
+		for _, name := range strings.Split(entry, "+") {
+			if name = strings.TrimSpace(name); name == "human" || name == "tui" {
+				fmt.Fprintf(os.Stderr, "%s needs a player, a tournament is between AI strategies\n", name)
+				os.Exit(2)
+			}
+		}
+		opts.Entries = append(opts.Entries, strings.TrimSpace(entry))
+	}
+	start := time.Now()
+	result, err := pkg.Tournament(opts)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	if *asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		enc.Encode(result)
+		return
+	}
+	fmt.Printf("%d deals per pairing from seed %d in %v, 95%% confidence intervals\n",
+		result.Deals, result.Seed, time.Since(start).Round(time.Millisecond))
+	fmt.Printf("%4s %-24s %6s %6s %7s  %s\n", "#", "entry", "hands", "wins", "points", "win rate")
+	for i, s := range result.Standings {
+		fmt.Printf("%4d %-24s %6d %6d %7d  %s\n", i+1, s.Entry, s.Hands, s.Wins, s.Points, s.WinRate)
+	}
+	fmt.Println()
+	for _, p := range result.Pairings {
+		fmt.Printf("%s vs %s: %d-%d hands, %d-%d points, %d-%d sweeps, %s wins %s\n",
+			p.A, p.B, p.WinsA, p.WinsB, p.PointsA, p.PointsB, p.SweepsA, p.SweepsB, p.A, p.WinRateA)
+	}
+}
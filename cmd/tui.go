@@ -0,0 +1,29 @@
+package main
+
+import (
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// rawTerminal lets the tui strategy read single key presses from stdin and
+// returns a function that restores the terminal.
+func rawTerminal() (restore func(), err error) {
+	state, err := stty("-g")
+	if err != nil {
+		return nil, err
+	}
+	if _, err = stty("-icanon", "-echo", "min", "1"); err != nil {
+		return nil, err
+	}
+	return func() {
+		stty(strings.TrimSpace(state))
+	}, nil
+}
+
+func stty(args ...string) (string, error) {
+	cmd := exec.Command("stty", args...)
+	cmd.Stdin = os.Stdin
+	out, err := cmd.Output()
+	return string(out), err
+}
@@ -2,4 +2,7 @@ module CardGame3V3Go
 
 go 1.13
 
-require github.com/stretchr/testify v1.4.0
+require (
+	github.com/stretchr/testify v1.4.0
+	gopkg.in/yaml.v2 v2.2.2
+)
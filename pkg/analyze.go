@@ -0,0 +1,105 @@
+package pkg
+
+import "fmt"
+
+// Analyzer reviews the decisions of a recorded hand. At every turn of a
+// reviewed seat it lets Search estimate the win rate of the shot played and
+// of the shots the search would consider, knowing only what the seat knew,
+// and reports the turns where a shot, or a pass, would have won at least
+// Threshold more often. Search searches on from every one of the shots, see
+// MCTSStrategy.WinRates.
+type Analyzer struct {
+	Search    *MCTSStrategy
+	Threshold float64
+	// Seats are the seats to review, the seats of a replay played by a
+	// human or tui strategy if nil.
+	Seats []int
+}
+
+func NewAnalyzer() *Analyzer {
+	return &Analyzer{
+		Search:    NewHardStrategy().Search,
+		Threshold: 0.15,
+	}
+}
+
+// Analysis is the review of a hand.
+type Analysis struct {
+	Seed      int64     `json:"seed"`
+	Seats     []int     `json:"seats"`
+	Names     [6]string `json:"names"`
+	Decisions int       `json:"decisions"`
+	Mistakes  []Mistake `json:"mistakes"`
+}
+
+// Mistake is a turn where Better was expected to win more often than the
+// shot Played, by Loss.
+type Mistake struct {
+	Turn      int     `json:"turn"`
+	Seat      int     `json:"seat"`
+	Played    Shot    `json:"played"`
+	PlayedWin float64 `json:"playedWin"`
+	Better    Shot    `json:"better"`
+	BetterWin float64 `json:"betterWin"`
+	Loss      float64 `json:"loss"`
+}
+
+// Analyze replays r and reviews the turns of the reviewed seats.
+func (a *Analyzer) Analyze(r *Replay) (analysis Analysis, err error) {
+	analysis.Seed = r.Seed
+	analysis.Names = r.Seats
+	analysis.Seats = a.Seats
+	if analysis.Seats == nil {
+		for seat, name := range r.Seats {
+			if name == "human" || name == "tui" {
+				analysis.Seats = append(analysis.Seats, seat)
+			}
+		}
+	}
+	reviewed := make(map[int]bool)
+	for _, seat := range analysis.Seats {
+		if seat < 0 || seat >= len(r.Seats) {
+			return analysis, fmt.Errorf("no seat %d", seat)
+		}
+		reviewed[seat] = true
+	}
+	g, err := r.Game(0)
+	if err != nil {
+		return
+	}
+	for _, move := range r.Moves {
+		if move.Turn != g.Turns()+1 {
+			return analysis, fmt.Errorf("turn %d: expected turn %d", move.Turn, g.Turns()+1)
+		}
+		played := move.Shot()
+		if reviewed[move.Seat] && move.Seat == g.CurrentPlayer() {
+			view, curShot := g.View(move.Seat), g.CurrentShot()
+			shots := distinctShots(append([]Shot{played}, a.Search.rootCandidates(view, curShot)...), view.Team)
+			if len(shots) > 1 {
+				analysis.Decisions++
+				rates := a.Search.WinRates(view, curShot, shots)
+				best := 0
+				for k := range shots {
+					if rates[k] > rates[best] {
+						best = k
+					}
+				}
+				if loss := rates[best] - rates[0]; loss >= a.Threshold {
+					analysis.Mistakes = append(analysis.Mistakes, Mistake{
+						Turn:      move.Turn,
+						Seat:      move.Seat,
+						Played:    shots[0],
+						PlayedWin: rates[0],
+						Better:    shots[best],
+						BetterWin: rates[best],
+						Loss:      loss,
+					})
+				}
+			}
+		}
+		if err = g.Apply(move.Seat, played); err != nil {
+			return analysis, fmt.Errorf("turn %d: %v", move.Turn, err)
+		}
+	}
+	return
+}
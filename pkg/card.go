@@ -1,5 +1,10 @@
 package pkg
 
+import (
+	"fmt"
+	"unicode"
+)
+
 const (
 	SPADE   CardColor = "spade"
 	HEART   CardColor = "heart"
@@ -25,6 +30,46 @@ var (
 		21: "小",
 		22: "大",
 	}
+	mapColorName = map[CardColor]string{
+		SPADE:   "S",
+		HEART:   "H",
+		CLUB:    "C",
+		DIAMOND: "D",
+	}
+	mapColorOrder = map[CardColor]int{
+		SPADE:   1,
+		HEART:   2,
+		CLUB:    3,
+		DIAMOND: 4,
+	}
+	mapRuneColor = map[rune]CardColor{
+		'S': SPADE,
+		'H': HEART,
+		'C': CLUB,
+		'D': DIAMOND,
+		'♠': SPADE,
+		'♥': HEART,
+		'♣': CLUB,
+		'♦': DIAMOND,
+	}
+	mapRuneNum = map[rune]uint32{
+		'3': 3,
+		'4': 4,
+		'5': 5,
+		'6': 6,
+		'7': 7,
+		'8': 8,
+		'9': 9,
+		'0': 10,
+		'T': 10,
+		'J': 11,
+		'Q': 12,
+		'K': 13,
+		'A': 14,
+		'2': 15,
+		'小': 21,
+		'大': 22,
+	}
 )
 
 type CardColor string
@@ -41,3 +86,45 @@ func (c *Card) Name() string {
 func (c *Card) Cmp(other *Card) uint32 {
 	return c.Num - other.Num
 }
+
+func (c Card) IsJoker() bool {
+	return c.Num == 21 || c.Num == 22
+}
+
+// String writes the card as its suit letter followed by its name, e.g. "S3",
+// "H0" for the ten of hearts or "DA". Jokers have no suit and are written
+// "小" and "大".
+func (c Card) String() string {
+	return mapColorName[c.Color] + c.Name()
+}
+
+// ParseCard reads a single card written by Card.String. The suit may also be
+// one of ♠♥♣♦ or left out, which gives a card without a color, the ten may
+// be written "10" or "T", and the jokers "BJ" and "RJ".
+func ParseCard(str string) (Card, error) {
+	cards, err := ParseCards(str)
+	if err != nil {
+		return Card{}, err
+	}
+	if len(cards) != 1 {
+		return Card{}, fmt.Errorf("bad card %q", str)
+	}
+	return cards[0], nil
+}
+
+func parseNum(runes []rune, i int) (num uint32, next int, ok bool) {
+	r := unicode.ToUpper(runes[i])
+	next = i + 1
+	if next < len(runes) {
+		switch {
+		case r == '1' && runes[next] == '0':
+			return 10, next + 1, true
+		case r == 'B' && unicode.ToUpper(runes[next]) == 'J':
+			return 21, next + 1, true
+		case r == 'R' && unicode.ToUpper(runes[next]) == 'J':
+			return 22, next + 1, true
+		}
+	}
+	num, ok = mapRuneNum[r]
+	return
+}
@@ -3,8 +3,8 @@ package pkg
 import (
 	"fmt"
 	"sort"
-	"strconv"
 	"strings"
+	"unicode"
 )
 
 type NumSorter []Card
@@ -15,152 +15,100 @@ func (s NumSorter) Less(i, j int) bool { return s[i].Num < s[j].Num }
 
 type Cards []Card
 
-func (c Cards) SplitInGroups() map[int][]string {
-	result := make(map[int][]string)
+// CardSorter orders cards by number and then by suit.
+type CardSorter []Card
 
-	cards := c
-	sort.Sort(NumSorter(cards))
-	cards = append(cards, Card{Num: 1})
-
-	curCard := ""
-	curCount := 0
-	for _, card := range cards {
-		name := card.Name()
-		if curCount == 0 {
-			curCard = name
-			curCount += 1
-		} else if name != "" && strings.HasPrefix(curCard, name) {
-			curCount += 1
-			curCard += name
-			if curCount == 5 {
-				result[5] = append(result[5], curCard)
-				curCount = 0
-			}
-		} else {
-			var idx int
-			if strings.HasPrefix(curCard, "小") || strings.HasPrefix(curCard, "大") {
-				idx = 6
-			} else {
-				idx = curCount
-			}
-			result[idx] = append(result[idx], curCard)
-			curCard = name
-			curCount = 1
-		}
+func (s CardSorter) Len() int      { return len(s) }
+func (s CardSorter) Swap(i, j int) { s[i], s[j] = s[j], s[i] }
+func (s CardSorter) Less(i, j int) bool {
+	if s[i].Num != s[j].Num {
+		return s[i].Num < s[j].Num
 	}
-	return result
+	return mapColorOrder[s[i].Color] < mapColorOrder[s[j].Color]
 }
 
-func (c *Cards) Get5Level() (level uint32, large uint32, err error) {
-	cards := *c
-	if len(cards) != 5 {
-		return 0, 0, fmt.Errorf("bad 5-cards %q", c)
+// Groups splits the cards into runs of the same number: index n-1 holds the
+// groups of n equal cards (five at most, larger runs are cut into several
+// groups) and index 5 holds the jokers.
+func (c Cards) Groups() (result [6][]Cards) {
+	cards := c.Copy()
+	sort.Sort(CardSorter(cards))
+	var cur Cards
+	flush := func() {
+		if len(cur) == 0 {
+			return
+		}
+		idx := len(cur) - 1
+		if cur[0].IsJoker() {
+			idx = 5
+		}
+		result[idx] = append(result[idx], cur)
+		cur = nil
 	}
-	sort.Sort(NumSorter(cards))
-
-	// straight, flush, full house, four, flush straight, five
-	cardsStr := cards.String()
-	largeCount := 0
-	largeNum := uint32(0)
 	for _, card := range cards {
-		count := strings.Count(cardsStr, card.Name())
-		if largeCount < count {
-			largeCount = count
-			large = card.Num
+		if len(cur) != 0 && cur[0].Num != card.Num {
+			flush()
+		}
+		cur = append(cur, card)
+		if len(cur) == 5 {
+			result[4] = append(result[4], cur)
+			cur = nil
 		}
-		if largeNum < card.Num {
-			largeNum = card.Num
+	}
+	flush()
+	return
+}
+
+// SplitInGroups is Groups with every group written by card names.
+func (c Cards) SplitInGroups() (result [6][]string) {
+	for idx, groups := range c.Groups() {
+		for _, cards := range groups {
+			result[idx] = append(result[idx], cards.names())
 		}
 	}
-	// five, four, full house
-	switch largeCount {
-	case 5:
-		return 5, large, nil
-	case 4:
-		return 3, large, nil
-	case 3:
-		return 2, large, nil
+	return
+}
+
+// Get5Level returns the FiveCardCategory of five cards as level, and the
+// first of its rank keys as large.
+func (c *Cards) Get5Level() (level uint32, large uint32, err error) {
+	rank, err := c.RankFive()
+	if err != nil {
+		return 0, 0, err
 	}
-	if c.checkStraight() && c.checkFlush() {
-		return 4, largeNum, nil
-	} else if c.checkFlush() {
-		return 1, largeNum, nil
-	} else if c.checkStraight() {
-		return 0, largeNum, nil
-	} else {
-		return 0, 0, fmt.Errorf("bad 5-cards %q", c)
+	return uint32(rank.Category), rank.Keys[0], nil
+}
+
+// String writes the cards separated by spaces, see Card.String.
+func (c Cards) String() string {
+	strs := make([]string, len(c))
+	for i, card := range c {
+		strs[i] = card.String()
 	}
+	return strings.Join(strs, " ")
 }
 
-func (c *Cards) checkStraight() bool {
-	cards := *c
-	sort.Sort(NumSorter(cards))
-	return len(cards) == 5 &&
-		cards[0].Num+1 == cards[1].Num &&
-		cards[1].Num+1 == cards[2].Num &&
-		cards[2].Num+1 == cards[3].Num &&
-		cards[3].Num+1 == cards[4].Num
+func (c Cards) MarshalText() ([]byte, error) {
+	return []byte(c.String()), nil
 }
 
-func (c *Cards) checkFlush() bool {
-	cards := *c
-	sort.Sort(NumSorter(cards))
-	return len(cards) == 5 &&
-		cards[0].Color == cards[1].Color &&
-		cards[1].Color == cards[2].Color &&
-		cards[2].Color == cards[3].Color &&
-		cards[3].Color == cards[4].Color
+func (c *Cards) UnmarshalText(text []byte) (err error) {
+	*c, err = ParseCards(string(text))
+	return
 }
 
-func (c Cards) String() (str string) {
+func (c Cards) names() (str string) {
 	for _, card := range c {
 		str += card.Name()
 	}
 	return
 }
 
+// Larger reports whether c beats o under the default rules. Both must be
+// valid shots of the same type.
 func (c *Cards) Larger(o *Cards) (bool, error) {
-	cards := *c
-	sort.Sort(NumSorter(cards))
-	others := *o
-	sort.Sort(NumSorter(others))
-	if len(cards) != len(others) {
-		return false, fmt.Errorf("cards length not equal: %q, %q", cards, others)
-	}
-	level1, large1, err := cards.validate()
-	if err != nil {
-		return false, err
-	}
-	level2, large2, err := others.validate()
-	if err != nil {
-		return false, err
-	}
-	if level1 > level2 {
-		return true, nil
-	} else if level1 < level2 {
-		return false, nil
-	} else {
-		return large1 > large2, nil
-	}
-}
-
-func (c *Cards) validate() (level uint32, large uint32, err error) {
-	cards := *c
-	sort.Sort(NumSorter(cards))
-	switch len(cards) {
-	case 1, 2, 3:
-		single := cards[0].Num
-		for _, card := range cards {
-			if card.Num != single {
-				return 0, 0, fmt.Errorf("bad cards %q", cards)
-			}
-		}
-		return 0, single, nil
-	case 5:
-		return c.Get5Level()
-	default:
-		return 0, 0, fmt.Errorf("bad cards: %q", cards)
-	}
+	cmp, err := defaultRules.Compare(*c, *o)
+	return cmp > 0, err
 }
 
 func (c Cards) Contains(target Card) bool {
@@ -172,11 +120,21 @@ func (c Cards) Contains(target Card) bool {
 	return false
 }
 
+// index returns the position of target in c, comparing colors only when
+// exact is set, or -1 when it is missing.
+func (c Cards) index(target Card, exact bool) int {
+	for i, card := range c {
+		if card.Num == target.Num && (!exact || card.Color == target.Color) {
+			return i
+		}
+	}
+	return -1
+}
+
 func (c Cards) Delete(target Card) (rs Cards) {
 	var deleted bool
 	for _, card := range c {
-		// TODO: card color
-		if !deleted && card.Num == target.Num {
+		if !deleted && card == target {
 			deleted = true
 		} else {
 			rs = append(rs, card)
@@ -185,57 +143,59 @@ func (c Cards) Delete(target Card) (rs Cards) {
 	return
 }
 
-func (c *Cards) Copy() (rs Cards) {
-	for _, card := range *c {
-		rs = append(rs, Card{
-			Num:   card.Num,
-			Color: card.Color,
-		})
+func (c Cards) Copy() (rs Cards) {
+	if len(c) == 0 {
+		return nil
 	}
+	rs = make(Cards, len(c))
+	copy(rs, c)
 	return
 }
 
-func CardStrToCards(cardStr string) (cards Cards) {
-	// TODO: Card Color
-	for _, char := range cardStr {
-		var card Card
-		if 50 < char && char <= 57 {
-			// 3~9
-			n, err := strconv.ParseUint(string(char), 10, 32)
-			if err != nil {
-				panic(err)
-			}
-			card.Num = uint32(n)
-		} else {
-			switch char {
-			case 48:
-				// 10
-				card.Num = 10
-			case 50:
-				// 2
-				card.Num = 15
-			case 74:
-				// J
-				card.Num = 11
-			case 81:
-				// Q
-				card.Num = 12
-			case 75:
-				// K
-				card.Num = 13
-			case 65:
-				// A
-				card.Num = 14
-			case 23567:
-				// 小
-				card.Num = 21
-			case 22823:
-				// 大
-				card.Num = 22
+// ParseCards reads cards written by Cards.String. Separators are optional,
+// so "S3 H3", "S3H3" and the suitless "33" are all accepted, see ParseCard.
+func ParseCards(str string) (cards Cards, err error) {
+	runes := []rune(str)
+	var color CardColor
+	for i := 0; i < len(runes); {
+		r := unicode.ToUpper(runes[i])
+		if unicode.IsSpace(r) || r == ',' {
+			if color != "" {
+				return nil, fmt.Errorf("bad cards %q: suit without number", str)
 			}
+			i++
+			continue
+		}
+		if c, ok := mapRuneColor[r]; ok && color == "" {
+			color = c
+			i++
+			continue
+		}
+		num, next, ok := parseNum(runes, i)
+		if !ok {
+			return nil, fmt.Errorf("bad cards %q: unknown card %q", str, string(runes[i]))
+		}
+		card := Card{Num: num, Color: color}
+		if card.IsJoker() && color != "" {
+			return nil, fmt.Errorf("bad cards %q: jokers have no suit", str)
 		}
 		cards = append(cards, card)
+		color = ""
+		i = next
+	}
+	if color != "" {
+		return nil, fmt.Errorf("bad cards %q: suit without number", str)
 	}
-	sort.Sort(NumSorter(cards))
+	sort.Sort(CardSorter(cards))
 	return
 }
+
+// CardStrToCards is ParseCards for strings known to be valid, it panics on
+// bad input.
+func CardStrToCards(cardStr string) Cards {
+	cards, err := ParseCards(cardStr)
+	if err != nil {
+		panic(err)
+	}
+	return cards
+}
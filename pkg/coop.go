@@ -0,0 +1,138 @@
+package pkg
+
+func init() {
+	RegisterStrategy("coop", func() Strategy { return CoopStrategy{NearOut: 3} })
+}
+
+// CoopStrategy plays like NormalStrategy but watches how many cards every
+// seat holds. A seat with NearOut cards or fewer is about to go out: when it
+// is a teammate, the strategy leads its smallest shots of a size the
+// teammate can follow and lets the teammate's shots through; when it is an
+// opponent, it spends its strongest shots to keep the lead, preferring the
+// smallest shot nobody can beat with the cards it has not seen, and leads
+// shots with more cards than the opponent holds.
+type CoopStrategy struct {
+	NearOut int
+}
+
+func (s CoopStrategy) NextShot(view TableView, curShot Shot) Shot {
+	rules := &view.Rules
+	if shot, err := view.ShotFor(view.Hand, curShot); err == nil {
+		return shot
+	}
+	partner, opponent := s.nearOut(view)
+	if curShot.Type == ShotTypePass {
+		if shot, ok := s.lead(view, partner, opponent); ok {
+			return shot
+		}
+		return NormalStrategy{}.NextShot(view, curShot)
+	}
+	if curShot.Team == view.Team {
+		if partner > 0 {
+			return Shot{Team: view.Team}
+		}
+		if opponent == 0 || view.Tracker().Unbeatable(curShot) {
+			return NormalStrategy{}.NextShot(view, curShot)
+		}
+		// the teammate's shot would not hold, take the lead for good
+		if shot, ok := s.unbeatable(view, rules.LegalShots(view.Hand, curShot)); ok {
+			return shot
+		}
+		return Shot{Team: view.Team}
+	}
+	if opponent > 0 {
+		if shot, ok := s.block(view, curShot); ok {
+			return shot
+		}
+	}
+	return NormalStrategy{}.NextShot(view, curShot)
+}
+
+// nearOut returns the fewest cards held by a teammate and by an opponent
+// who are about to go out, or 0 if none is.
+func (s CoopStrategy) nearOut(view TableView) (partner, opponent int) {
+	for seat, n := range view.CardCounts {
+		if seat == view.Seat || view.Finished[seat] || n == 0 || n > s.NearOut {
+			continue
+		}
+		if view.Teams[seat] == view.Team {
+			if partner == 0 || n < partner {
+				partner = n
+			}
+		} else if opponent == 0 || n < opponent {
+			opponent = n
+		}
+	}
+	return
+}
+
+func (s CoopStrategy) lead(view TableView, partner, opponent int) (Shot, bool) {
+	shots := view.Rules.LegalShots(view.Hand, Shot{})
+	if opponent > 0 {
+		// an opponent cannot follow a shot of more cards than it holds
+		for _, shot := range shots {
+			if shot.Type != ShotTypeBomb && len(shot.Cards) > opponent {
+				shot.Team = view.Team
+				return shot, true
+			}
+		}
+		if shot, ok := s.unbeatable(view, shots); ok {
+			return shot, true
+		}
+		for i := len(shots) - 1; i >= 0; i-- {
+			if shots[i].Type == ShotTypeOne {
+				shots[i].Team = view.Team
+				return shots[i], true
+			}
+		}
+		return Shot{}, false
+	}
+	if partner > 0 {
+		// the smallest shot the teammate may follow, breaking no group
+		groups := view.Hand.Groups()
+		for n := 1; n <= partner && n <= 3; n++ {
+			for _, cards := range groups[n-1] {
+				if t, err := view.Rules.ShotTypeOf(cards); err == nil && t == ShotType(n) {
+					return Shot{
+						Cards: cards,
+						Type:  t,
+						Team:  view.Team,
+					}, true
+				}
+			}
+		}
+	}
+	return Shot{}, false
+}
+
+// block beats curShot of an opponent with the smallest unbeatable shot, or
+// else with the largest shot of its type.
+func (s CoopStrategy) block(view TableView, curShot Shot) (Shot, bool) {
+	shots := view.Rules.LegalShots(view.Hand, curShot)
+	if shot, ok := s.unbeatable(view, shots); ok {
+		return shot, true
+	}
+	for i := len(shots) - 1; i >= 0; i-- {
+		if shots[i].Type == curShot.Type {
+			shots[i].Team = view.Team
+			return shots[i], true
+		}
+	}
+	if len(shots) == 0 {
+		return Shot{}, false
+	}
+	shots[0].Team = view.Team
+	return shots[0], true
+}
+
+// unbeatable returns the first of shots nobody can beat.
+func (s CoopStrategy) unbeatable(view TableView, shots []Shot) (Shot, bool) {
+	tracker := view.Tracker()
+	for _, shot := range shots {
+		if tracker.Unbeatable(shot) {
+			shot.Team = view.Team
+			return shot, true
+		}
+	}
+	return Shot{}, false
+}
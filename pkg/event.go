@@ -0,0 +1,80 @@
+package pkg
+
+// Event is something that happened in a Game: HandDealt, ShotPlayed,
+// Passed, RoundReset, PlayerFinished or HandOver. Turn counts the shots and
+// passes of the hand, see Game.Turns.
+type Event interface {
+	event()
+}
+
+// HandDealt is sent when a hand starts, with the hands as they are played
+// after any tribute and the seat that leads the first round.
+type HandDealt struct {
+	Hands  [6]Cards
+	Leader int
+}
+
+type ShotPlayed struct {
+	Seat int
+	Turn int
+	Shot Shot
+}
+
+type Passed struct {
+	Seat int
+	Turn int
+}
+
+// RoundReset is sent when everybody passed on the last shot and Leader
+// starts a new round.
+type RoundReset struct {
+	Leader int
+	Turn   int
+}
+
+type PlayerFinished struct {
+	Finish
+	Place int
+}
+
+type HandOver struct {
+	Result HandResult
+}
+
+func (HandDealt) event()      {}
+func (ShotPlayed) event()     {}
+func (Passed) event()         {}
+func (RoundReset) event()     {}
+func (PlayerFinished) event() {}
+func (HandOver) event()       {}
+
+// Observer receives the events of the games it subscribed to. It is called
+// synchronously by the Game and must not change it.
+type Observer interface {
+	OnEvent(e Event)
+}
+
+type ObserverFunc func(e Event)
+
+func (f ObserverFunc) OnEvent(e Event) {
+	f(e)
+}
+
+// ChanObserver sends the events to ch. The game blocks until each event is
+// received, unless ch is buffered.
+func ChanObserver(ch chan<- Event) Observer {
+	return ObserverFunc(func(e Event) {
+		ch <- e
+	})
+}
+
+// Subscribe registers o for the events of the game.
+func (g *Game) Subscribe(o Observer) {
+	g.observers = append(g.observers, o)
+}
+
+func (g *Game) emit(e Event) {
+	for _, o := range g.observers {
+		o.OnEvent(e)
+	}
+}
@@ -0,0 +1,86 @@
+package pkg
+
+// FiveCardCategory is the kind of a five-card shot. The categories are
+// totally ordered from FiveCardStraight, the weakest, to FiveCardFiveOfAKind,
+// the strongest, and a shot of a stronger category beats any shot of a
+// weaker one.
+//
+// A straight is five consecutive numbers from 3-4-5-6-7 up to J-Q-K-A-2; the
+// 2 ranks above the ace and does not wrap around. A flush is five cards of
+// one suit, repeated cards included. Jokers have no suit and are never part
+// of a straight or a flush, but they may form the groups of a full house,
+// four of a kind or five of a kind, or be the odd card of a four of a kind.
+// When five cards fit several categories the strongest one counts.
+type FiveCardCategory uint32
+
+const (
+	FiveCardStraight FiveCardCategory = iota
+	FiveCardFlush
+	FiveCardFullHouse
+	FiveCardFourOfAKind
+	FiveCardStraightFlush
+	FiveCardFiveOfAKind
+)
+
+var mapFiveCardCategoryName = map[FiveCardCategory]string{
+	FiveCardStraight:      "straight",
+	FiveCardFlush:         "flush",
+	FiveCardFullHouse:     "full house",
+	FiveCardFourOfAKind:   "four of a kind",
+	FiveCardStraightFlush: "straight flush",
+	FiveCardFiveOfAKind:   "five of a kind",
+}
+
+func (c FiveCardCategory) String() string {
+	return mapFiveCardCategoryName[c]
+}
+
+// FiveCardRank orders five-card shots. Ranks of the same category compare
+// their Keys in order:
+//   - straight and straight flush: the highest number;
+//   - flush: the numbers from the highest to the lowest;
+//   - full house: the triple, then the pair;
+//   - four of a kind: the four, then the odd card;
+//   - five of a kind: the number.
+type FiveCardRank struct {
+	Category FiveCardCategory
+	Keys     []uint32
+}
+
+// Compare returns 1 if r beats o, -1 if o beats r and 0 if neither does.
+func (r FiveCardRank) Compare(o FiveCardRank) int {
+	if r.Category != o.Category {
+		if r.Category > o.Category {
+			return 1
+		}
+		return -1
+	}
+	for i := 0; i < len(r.Keys) && i < len(o.Keys); i++ {
+		if r.Keys[i] > o.Keys[i] {
+			return 1
+		} else if r.Keys[i] < o.Keys[i] {
+			return -1
+		}
+	}
+	return 0
+}
+
+// RankFive categorizes five cards under the default rules, see
+// FiveCardCategory.
+func (c Cards) RankFive() (FiveCardRank, error) {
+	return defaultRules.RankFive(c)
+}
+
+// CompareFive returns 1 if a beats b, -1 if b beats a and 0 if neither
+// does. It fails unless both are valid five-card shots.
+func CompareFive(a, b Cards) (int, error) {
+	ra, err := a.RankFive()
+	if err != nil {
+		return 0, err
+	}
+	rb, err := b.RankFive()
+	if err != nil {
+		return 0, err
+	}
+	return ra.Compare(rb), nil
+}
@@ -1,79 +1,326 @@
 package pkg
 
 import (
+	"errors"
 	"fmt"
 	"math/rand"
+	"sort"
 	"time"
 )
 
+var ErrGameOver = errors.New("game is over")
+
 type Game struct {
-	Players         [6]Player
-	FinishedPlayers map[int]struct{}
+	Players [6]Player
+	// FinishedPlayers lists the seats that went out, in order.
+	FinishedPlayers []Finish
+	Rules           Rules
+	Seed            int64
+	rng             *rand.Rand
+	curShot         Shot
+	curPlayer       int
+	numPasses       int
+	turns           int
+	played          [6]Cards
+	observers       []Observer
+}
+
+// Finish records that Seat played its last card on turn Turn, counting
+// every shot and pass of the hand from 1.
+type Finish struct {
+	Seat int `json:"seat"`
+	Turn int `json:"turn"`
+}
+
+// HandResult is the outcome of a played hand. Ranking orders all seats from
+// the first to the last place, see Game.Ranking.
+type HandResult struct {
+	Winner    uint32   `json:"winner"`
+	Finished  []Finish `json:"finished"`
+	Ranking   []int    `json:"ranking"`
+	Remaining [6]Cards `json:"remaining"`
+	Turns     int      `json:"turns"`
 }
 
-func init() {
-	rand.Seed(time.Now().UnixNano())
+// GameOptions controls how a Game draws its random decisions. If Source is
+// nil a source is created from Seed, and a zero Seed is replaced by a
+// time-based one, so Game.Seed always reproduces the deal. A nil Rules plays
+// by DefaultRules, other rules must pass Rules.Validate.
+type GameOptions struct {
+	Seed   int64
+	Source rand.Source
+	Rules  *Rules
 }
 
-func NewGame() (g Game) {
-	for i := 0; i < 3; i++ {
-		g.Players[2*i].Team = 1
+// NewGame sets up a game of opts. It panics if opts.Rules do not validate.
+func NewGame(opts GameOptions) (g Game) {
+	if opts.Rules != nil {
+		g.Rules = *opts.Rules
+		if err := g.Rules.Validate(); err != nil {
+			panic(fmt.Sprintf("bad rules: %v", err))
+		}
+	} else {
+		g.Rules = DefaultRules()
+	}
+	for i := range g.Players {
+		g.Players[i].Team = g.Rules.Teams[i]
+		g.Players[i].Strategy = NormalStrategy{}
 	}
-	g.Players[0].Type = PlayerTypeUser
-	g.FinishedPlayers = make(map[int]struct{})
+	if opts.Source == nil {
+		if opts.Seed == 0 {
+			opts.Seed = time.Now().UnixNano()
+		}
+		opts.Source = rand.NewSource(opts.Seed)
+	}
+	g.Seed = opts.Seed
+	g.rng = rand.New(opts.Source)
 	return
 }
 
+// Start deals the cards and picks the player who leads the first round.
 func (g *Game) Start() {
+	g.Deal()
+	g.SetLeader(g.rng.Intn(6))
+}
+
+// Deal clears the table and deals new hands, keeping the seats' teams and
+// strategies. SetLeader must be called before the hand is played.
+func (g *Game) Deal() {
+	g.DealHands([6]Cards{})
 	g.AssignCards()
-	var curShot Shot
-	curPlayer := rand.Intn(6)
-	bigPlayer := curPlayer
-	numPasses := g.ResetNumPasses()
-	for !g.isFinished() {
-		if numPasses == 0 {
-			curShot = Shot{}
-			g.ShowCards()
-			numPasses = g.ResetNumPasses()
+}
+
+// DealHands is Deal with the given hands instead of shuffled ones.
+func (g *Game) DealHands(hands [6]Cards) {
+	for i := range g.Players {
+		g.Players[i].Cards = hands[i].Copy()
+	}
+	g.FinishedPlayers = nil
+	g.turns = 0
+	g.played = [6]Cards{}
+}
+
+// NewGameAt sets up a game in the state seen by view with the given hands,
+// view's seat to play on curShot. It plays a hand out from a guess of the
+// cards the seat cannot see.
+func NewGameAt(view TableView, curShot Shot, hands [6]Cards) (g Game) {
+	g = NewGame(GameOptions{
+		Seed:  1,
+		Rules: &view.Rules,
+	})
+	g.DealHands(hands)
+	g.FinishedPlayers = append([]Finish(nil), view.Finishes...)
+	g.turns = view.Turn
+	for i := range view.Played {
+		g.played[i] = view.Played[i].Copy()
+	}
+	g.curShot = curShot
+	g.curPlayer = view.Seat
+	g.numPasses = view.PassesLeft
+	return
+}
+
+// SetLeader lets seat lead the first round of the hand.
+func (g *Game) SetLeader(seat int) {
+	g.curShot = Shot{}
+	g.curPlayer = seat
+	g.numPasses = g.ResetNumPasses()
+	if len(g.observers) != 0 {
+		e := HandDealt{
+			Leader: seat,
 		}
-		shot := g.Players[curPlayer].NextShot(curShot)
-		if shot.Type != ShotTypePass {
-			curShot = shot
-			bigPlayer = curPlayer
-			numPasses = g.ResetNumPasses()
-		} else {
-			numPasses -= 1
+		for i := range g.Players {
+			e.Hands[i] = Cards(g.Players[i].Cards).Copy()
+		}
+		g.emit(e)
+	}
+}
+
+func (g *Game) CurrentPlayer() int {
+	return g.curPlayer
+}
+
+// CurrentShot returns the shot to beat, or a pass when the current player
+// leads a new round.
+func (g *Game) CurrentShot() Shot {
+	return g.curShot
+}
+
+// Turns returns the number of shots and passes applied in this hand.
+func (g *Game) Turns() int {
+	return g.turns
+}
+
+// Play asks the seats' strategies for their shots until the game is over.
+func (g *Game) Play() (HandResult, error) {
+	for !g.IsFinished() {
+		curPlayer := g.curPlayer
+		shot := g.Players[curPlayer].NextShot(g.View(curPlayer), g.curShot)
+		if err := g.Apply(curPlayer, shot); err != nil {
+			return HandResult{}, fmt.Errorf("Player%d: %v", curPlayer, err)
+		}
+	}
+	return g.Result()
+}
+
+// Result returns the outcome of the hand once it is over.
+func (g *Game) Result() (result HandResult, err error) {
+	if len(g.FinishedPlayers) == 0 || !g.IsFinished() {
+		return result, fmt.Errorf("hand is not over")
+	}
+	result.Winner = g.Winner()
+	result.Finished = append([]Finish(nil), g.FinishedPlayers...)
+	result.Ranking = g.Ranking()
+	for i := range g.Players {
+		result.Remaining[i] = Cards(g.Players[i].Cards).Copy()
+	}
+	result.Turns = g.turns
+	return
+}
+
+// View returns what seat can see of the table.
+func (g *Game) View(seat int) (view TableView) {
+	view.Seat = seat
+	view.Team = g.Players[seat].Team
+	view.Hand = Cards(g.Players[seat].Cards).Copy()
+	view.Rules = g.Rules
+	for i := range g.Players {
+		view.Teams[i] = g.Players[i].Team
+		view.CardCounts[i] = len(g.Players[i].Cards)
+		view.Finished[i] = g.Players[i].IsFinished()
+		view.Played[i] = g.played[i].Copy()
+	}
+	view.Finishes = append([]Finish(nil), g.FinishedPlayers...)
+	view.Turn = g.turns
+	view.PassesLeft = g.numPasses
+	return
+}
+
+// Apply plays shot for playerIdx, who must be the current player. The shot
+// is checked against the player's hand and the current shot, and the game is
+// left untouched when it is rejected.
+func (g *Game) Apply(playerIdx int, shot Shot) error {
+	if g.IsFinished() {
+		return ErrGameOver
+	}
+	if playerIdx != g.curPlayer {
+		return fmt.Errorf("not Player%d's turn, waiting for Player%d", playerIdx, g.curPlayer)
+	}
+	p := &g.Players[playerIdx]
+	if shot.Type == ShotTypePass {
+		if g.curShot.Type == ShotTypePass {
+			return fmt.Errorf("Player%d leads the round and cannot pass", playerIdx)
 		}
-		fmt.Printf("Player%d: %s, numPasses=%d\n", curPlayer, shot.Cards, numPasses)
-		if g.Players[curPlayer].IsFinished() {
-			fmt.Printf("Player%d finishes\n", curPlayer)
-			g.FinishedPlayers[curPlayer] = struct{}{}
-			bigPlayer = g.NextPlayer(bigPlayer)
+		g.numPasses -= 1
+		g.turns++
+		g.emit(Passed{
+			Seat: playerIdx,
+			Turn: g.turns,
+		})
+	} else {
+		if err := g.Rules.CheckShot(p.Cards, g.curShot, shot); err != nil {
+			return err
 		}
-		curPlayer = g.NextPlayer(curPlayer)
+		p.RemoveCards(shot.Cards)
+		g.played[playerIdx] = append(g.played[playerIdx], shot.Cards...)
+		g.curShot = Shot{
+			Cards: shot.Cards,
+			Type:  shot.Type,
+			Team:  p.Team,
+		}
+		g.numPasses = g.ResetNumPasses()
+		g.turns++
+		g.emit(ShotPlayed{
+			Seat: playerIdx,
+			Turn: g.turns,
+			Shot: g.curShot,
+		})
+	}
+	if p.IsFinished() {
+		finish := Finish{
+			Seat: playerIdx,
+			Turn: g.turns,
+		}
+		g.FinishedPlayers = append(g.FinishedPlayers, finish)
+		g.emit(PlayerFinished{
+			Finish: finish,
+			Place:  len(g.FinishedPlayers),
+		})
+	}
+	if g.IsFinished() {
+		if len(g.observers) != 0 {
+			result, _ := g.Result()
+			g.emit(HandOver{
+				Result: result,
+			})
+		}
+		return nil
+	}
+	g.curPlayer = g.NextPlayer(playerIdx)
+	if g.numPasses == 0 {
+		g.curShot = Shot{}
+		g.numPasses = g.ResetNumPasses()
+		g.emit(RoundReset{
+			Leader: g.curPlayer,
+			Turn:   g.turns,
+		})
+	}
+	return nil
+}
+
+// LegalShots lists the shots playerIdx may play on the current shot. Passing
+// is not included.
+func (g *Game) LegalShots(playerIdx int) []Shot {
+	p := &g.Players[playerIdx]
+	shots := g.Rules.LegalShots(p.Cards, g.curShot)
+	for i := range shots {
+		shots[i].Team = p.Team
 	}
+	return shots
 }
 
 func (g *Game) ResetNumPasses() int {
 	return 6 - 1 - len(g.FinishedPlayers)
 }
 
-func (g *Game) isFinished() bool {
-	_, ok1 := g.FinishedPlayers[0]
-	_, ok2 := g.FinishedPlayers[2]
-	_, ok3 := g.FinishedPlayers[4]
-	if ok1 && ok2 && ok3 {
-		fmt.Printf("Team 1 wins! \n%v\n", g.FinishedPlayers)
-		return true
+func (g *Game) IsFinished() bool {
+	return g.Winner() != 0
+}
+
+// Winner returns the team whose players have all finished, or 0 while the
+// game is still running.
+func (g *Game) Winner() uint32 {
+	unfinished := make(map[uint32]bool)
+	for i := range g.Players {
+		if !g.Players[i].IsFinished() {
+			unfinished[g.Players[i].Team] = true
+		}
+	}
+	for i := range g.Players {
+		if team := g.Players[i].Team; !unfinished[team] {
+			return team
+		}
 	}
-	_, ok1 = g.FinishedPlayers[1]
-	_, ok2 = g.FinishedPlayers[3]
-	_, ok3 = g.FinishedPlayers[5]
-	if ok1 && ok2 && ok3 {
-		fmt.Printf("Team 2 wins! \n%v\n", g.FinishedPlayers)
-		return true
+	return 0
+}
+
+// Ranking orders all seats from the first to the last place: the finished
+// seats in the order they went out, then the others by the number of cards
+// left.
+func (g *Game) Ranking() []int {
+	var ranking, rest []int
+	for _, finish := range g.FinishedPlayers {
+		ranking = append(ranking, finish.Seat)
+	}
+	for i := range g.Players {
+		if !g.Players[i].IsFinished() {
+			rest = append(rest, i)
+		}
 	}
-	return false
+	sort.SliceStable(rest, func(i, j int) bool {
+		return len(g.Players[rest[i]].Cards) < len(g.Players[rest[j]].Cards)
+	})
+	return append(ranking, rest...)
 }
 
 func (g *Game) NextPlayer(cur int) int {
@@ -83,7 +330,7 @@ func (g *Game) NextPlayer(cur int) int {
 		} else {
 			cur += 1
 		}
-		if _, ok := g.FinishedPlayers[cur]; !ok {
+		if !g.Players[cur].IsFinished() {
 			return cur
 		}
 	}
@@ -111,35 +358,15 @@ func initialCard(num int) Cards {
 	}
 }
 
-func initialCards() (cards Cards) {
-	for num := 0; num < 3; num++ {
-		for i := 3; i <= 15; i++ {
-			cards = append(cards, initialCard(i)...)
-		}
-		cards = append(cards, Card{Num: 21})
-		cards = append(cards, Card{Num: 22})
-	}
-	return
-}
-
 func (g *Game) AssignCards() {
-	cards := initialCards()
-	rand.Shuffle(len(cards), func(i, j int) {
+	cards := g.Rules.Deck()
+	g.rng.Shuffle(len(cards), func(i, j int) {
 		cards[i], cards[j] = cards[j], cards[i]
 	})
 	for i := 0; i < len(cards); i++ {
 		g.Players[i%6].AddCard(cards[i])
 	}
-	g.ShowCards()
-}
-
-func (g *Game) ShowCards() {
-	fmt.Println("========== all cards ==========")
-	for i := 0; i < len(g.Players); i++ {
-		if _, ok := g.FinishedPlayers[i]; !ok {
-			fmt.Printf("Player%d: ", i)
-			g.Players[i].ShowCards()
-		}
+	for i := range g.Players {
+		sort.Sort(CardSorter(g.Players[i].Cards))
 	}
-	fmt.Println("===============================")
 }
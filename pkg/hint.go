@@ -0,0 +1,187 @@
+package pkg
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Hint is a shot the seat may play, scored by Hints, with the reasons for
+// its score.
+type Hint struct {
+	Shot    Shot     `json:"shot"`
+	Score   float64  `json:"score"`
+	Reasons []string `json:"reasons"`
+}
+
+// Hints ranks the shots the seat of view may play on curShot, passing
+// included, from the best to the worst. Equal shots of different suits are
+// listed once.
+//
+// A shot scores by how many more shots the rest of the hand needs than the
+// plan of PlanHand, and lower shots score a little better. Going out comes
+// first, shots nobody can beat with the unseen cards gain, and so do shots
+// that help a teammate about to go out or stop an opponent about to; bombs,
+// less so against such an opponent, and playing over a teammate cost.
+// Passing keeps the cards, which is good on a teammate's shot and bad when
+// an opponent is about to go out.
+func Hints(view TableView, curShot Shot) (hints []Hint) {
+	rules := &view.Rules
+	hand := view.Hand.Copy()
+	sort.Sort(CardSorter(hand))
+	p := newPlanner(rules)
+	plan := p.planCards(hand)
+	tracker := view.Tracker()
+	var partners, opponents []int
+	for seat, n := range view.CardCounts {
+		if seat == view.Seat || view.Finished[seat] || n == 0 || n > 3 {
+			continue
+		}
+		if view.Teams[seat] == view.Team {
+			partners = append(partners, seat)
+		} else {
+			opponents = append(opponents, seat)
+		}
+	}
+	friend := curShot.Type != ShotTypePass && curShot.Team == view.Team
+
+	tried := make(map[string]bool)
+	for _, shot := range rules.LegalShots(hand, curShot) {
+		key := string(rune('0'+shot.Type)) + string(numsKey(nums(shot.Cards)))
+		if tried[key] {
+			continue
+		}
+		tried[key] = true
+		shot.Team = view.Team
+		hint := Hint{Shot: shot}
+		rest := without(hand, shot.Cards)
+		if len(rest) == 0 {
+			hint.Score = 100
+			hint.Reasons = append(hint.Reasons, "goes out")
+			hints = append(hints, hint)
+			continue
+		}
+		left := len(p.planCards(rest))
+		cost := left + 1 - len(plan)
+		if cost < 0 {
+			cost = 0
+		}
+		hint.Score = -2*float64(cost) - 0.5*float64(rules.Rank(shot.Cards[len(shot.Cards)-1].Num))/float64(rules.Rank(22))
+		for _, planned := range plan {
+			if string(numsKey(nums(planned.Cards))) == string(numsKey(nums(shot.Cards))) {
+				hint.Reasons = append(hint.Reasons, "plays your planned "+describeShot(rules, planned))
+			} else if len(planned.Cards) > 1 && sharesCards(planned.Cards, shot.Cards) && cost > 0 {
+				hint.Reasons = append(hint.Reasons, fmt.Sprintf("breaks your %s %s", describeShot(rules, planned), planned.Cards))
+			}
+		}
+		if cost > 0 {
+			hint.Reasons = append(hint.Reasons, fmt.Sprintf("leaves %d shots to play instead of %d", left, len(plan)-1))
+		} else if shot.Type != ShotTypeBomb {
+			for _, planned := range plan {
+				if _, err := rules.RankBomb(planned.Cards); err == nil && !sharesCards(planned.Cards, shot.Cards) {
+					hint.Reasons = append(hint.Reasons, "keeps your "+describeShot(rules, planned))
+				}
+			}
+		}
+		if tracker.Unbeatable(shot) {
+			hint.Score++
+			hint.Reasons = append(hint.Reasons, "nobody can beat it")
+		}
+		if friend {
+			hint.Score--
+			hint.Reasons = append(hint.Reasons, "plays over your partner's shot")
+		}
+		if shot.Type == ShotTypeBomb && curShot.Type != ShotTypeBomb {
+			hint.Score -= 1.5
+			if len(opponents) != 0 {
+				hint.Score++
+			}
+			hint.Reasons = append(hint.Reasons, "spends your "+describeShot(rules, shot))
+		}
+		for _, seat := range opponents {
+			n := view.CardCounts[seat]
+			switch {
+			case curShot.Type != ShotTypePass && !friend:
+				hint.Score += 1.5
+				hint.Reasons = append(hint.Reasons, fmt.Sprintf("stops Player%d who has %d cards left", seat, n))
+			case curShot.Type == ShotTypePass && len(shot.Cards) > n:
+				hint.Score++
+				hint.Reasons = append(hint.Reasons, fmt.Sprintf("Player%d cannot follow with %d cards left", seat, n))
+			case curShot.Type == ShotTypePass:
+				hint.Score--
+				hint.Reasons = append(hint.Reasons, fmt.Sprintf("Player%d has %d cards left and may follow", seat, n))
+			}
+		}
+		if curShot.Type == ShotTypePass {
+			for _, seat := range partners {
+				if n := view.CardCounts[seat]; len(shot.Cards) <= n && shot.Type != ShotTypeBomb {
+					hint.Score++
+					hint.Reasons = append(hint.Reasons, fmt.Sprintf("partner Player%d has %d cards left and may follow", seat, n))
+				}
+			}
+		}
+		hints = append(hints, hint)
+	}
+
+	if curShot.Type != ShotTypePass {
+		hint := Hint{
+			Shot:  Shot{Team: view.Team},
+			Score: -1,
+		}
+		switch {
+		case len(hints) == 0:
+			hint.Reasons = append(hint.Reasons, "nothing beats it")
+		case friend:
+			hint.Score += 1.5
+			hint.Reasons = append(hint.Reasons, "lets your partner's shot through")
+			for _, seat := range partners {
+				hint.Score++
+				hint.Reasons = append(hint.Reasons, fmt.Sprintf("partner Player%d has %d cards left", seat, view.CardCounts[seat]))
+			}
+		default:
+			hint.Reasons = append(hint.Reasons, "keeps your cards")
+			for _, seat := range opponents {
+				hint.Score -= 2
+				hint.Reasons = append(hint.Reasons, fmt.Sprintf("Player%d has %d cards left", seat, view.CardCounts[seat]))
+			}
+		}
+		hints = append(hints, hint)
+	}
+	sort.SliceStable(hints, func(i, j int) bool {
+		return hints[i].Score > hints[j].Score
+	})
+	return
+}
+
+// describeShot names the kind of shot, like "pair" or "straight flush".
+func describeShot(rules *Rules, shot Shot) string {
+	switch shot.Type {
+	case ShotTypeOne:
+		return "single"
+	case ShotTypeTwo:
+		return "pair"
+	case ShotTypeThree:
+		return "triple"
+	case ShotTypeFive:
+		if rank, err := rules.RankFive(shot.Cards); err == nil {
+			return rank.Category.String()
+		}
+	case ShotTypeBomb:
+		if rank, err := rules.RankBomb(shot.Cards); err == nil && rank.Kind == BombStraightFlush {
+			return "straight flush"
+		}
+		return "bomb"
+	}
+	return "shot"
+}
+
+// sharesCards reports whether a and b have a card of the same number.
+func sharesCards(a, b Cards) bool {
+	for _, x := range a {
+		for _, y := range b {
+			if x.Num == y.Num {
+				return true
+			}
+		}
+	}
+	return false
+}
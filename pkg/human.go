@@ -0,0 +1,99 @@
+package pkg
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// HumanStrategy prompts for every shot on Out and reads the typed cards from
+// In, asking again until they form a legal shot. Typing "hint" lists the
+// best shots of Hints unless Hints is off. Once In fails, e.g. at the end
+// of the input, Err holds the error and the seat passes whenever it may and
+// otherwise leads the first of its legal shots, so the caller can end the
+// game.
+type HumanStrategy struct {
+	In    *bufio.Reader
+	Out   io.Writer
+	Hints bool
+	Err   error
+}
+
+// MaxHints is the number of hints shown to a human player.
+const MaxHints = 5
+
+// NewHumanStrategy reads from in, which is used as is when it is a
+// *bufio.Reader, so that seats sharing an input can share its buffer too.
+func NewHumanStrategy(in io.Reader, out io.Writer) *HumanStrategy {
+	return &HumanStrategy{
+		In:    bufio.NewReader(in),
+		Out:   out,
+		Hints: true,
+	}
+}
+
+func (s *HumanStrategy) NextShot(view TableView, curShot Shot) Shot {
+	p := &Player{
+		Cards: view.Hand.Copy(),
+		Team:  view.Team,
+	}
+	for s.Err == nil {
+		fmt.Fprintf(s.Out, "Current cards: %s, len=%d\n", Cards(p.Cards), len(p.Cards))
+		fmt.Fprintf(s.Out, "All 5 combos: ")
+		for _, cards := range p.FormFive() {
+			fmt.Fprintf(s.Out, "[%s] ", cards)
+		}
+		fmt.Fprintln(s.Out, "")
+		friend := curShot.Type != ShotTypePass && curShot.Team == p.Team
+		if s.Hints {
+			fmt.Fprintf(s.Out, "Please type your next shot or hint, friend=%v: \n", friend)
+		} else {
+			fmt.Fprintf(s.Out, "Please type your next shot, friend=%v: \n", friend)
+		}
+		cardStr, err := s.In.ReadString('\n')
+		if err != nil && (err != io.EOF || cardStr == "") {
+			s.Err = err
+			break
+		}
+		cardStr = strings.TrimSpace(cardStr)
+		if strings.ToLower(cardStr) == "hint" {
+			s.showHints(view, curShot)
+			continue
+		}
+		if strings.HasPrefix("pass", strings.ToLower(cardStr)) {
+			if curShot.Type != ShotTypePass {
+				return Shot{Team: p.Team}
+			}
+			fmt.Fprintln(s.Out, "Oops, you lead this round and cannot pass! Please try again:")
+			continue
+		}
+		cards, err := ParseCards(cardStr)
+		if err == nil {
+			var shot Shot
+			if shot, err = view.ShotFor(cards, curShot); err == nil {
+				return shot
+			}
+		}
+		fmt.Fprintf(s.Out, "Oops, %v! Please try again:\n", err)
+	}
+	if curShot.Type != ShotTypePass {
+		return Shot{Team: p.Team}
+	}
+	shot := view.Rules.LegalShots(view.Hand, curShot)[0]
+	shot.Team = p.Team
+	return shot
+}
+
+func (s *HumanStrategy) showHints(view TableView, curShot Shot) {
+	if !s.Hints {
+		fmt.Fprintln(s.Out, "Hints are off.")
+		return
+	}
+	for i, hint := range Hints(view, curShot) {
+		if i == MaxHints {
+			break
+		}
+		fmt.Fprintf(s.Out, "%d. %s: %s\n", i+1, hint.Shot, strings.Join(hint.Reasons, ", "))
+	}
+}
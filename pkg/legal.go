@@ -0,0 +1,246 @@
+package pkg
+
+import "sort"
+
+// LegalShots lists every shot hand can play on cur, or every shot it can
+// lead when cur is a pass, under the default rules. Passing is not
+// included. Equal cards from different decks are interchangeable, so each
+// combination of cards is listed once. Shots come ordered by type and,
+// within a type, from the smallest to the largest.
+func LegalShots(hand Cards, cur Shot) []Shot {
+	return defaultRules.LegalShots(hand, cur)
+}
+
+// LegalShots is the package LegalShots under r.
+func (r *Rules) LegalShots(hand Cards, cur Shot) (shots []Shot) {
+	cards := hand.Copy()
+	sort.Sort(CardSorter(cards))
+	for _, t := range r.ShotTypes {
+		if cur.Type != ShotTypePass && cur.Type != t && !(t == ShotTypeFive && r.fiveBeatsAnyType()) {
+			continue
+		}
+		var candidates []Cards
+		if t == ShotTypeFive {
+			candidates = r.fiveCombos(cards)
+		} else {
+			for _, same := range runs(cards) {
+				combos(same, int(t), func(c Cards) {
+					candidates = append(candidates, c.Copy())
+				})
+			}
+		}
+		var typed []Shot
+		for _, c := range candidates {
+			shot := Shot{
+				Cards: c,
+				Type:  t,
+			}
+			if r.Beats(shot, cur) == nil {
+				typed = append(typed, shot)
+			}
+		}
+		if t == ShotTypeFive {
+			ranks := make([]FiveCardRank, len(typed))
+			idx := make([]int, len(typed))
+			for i := range typed {
+				ranks[i], _ = r.RankFive(typed[i].Cards)
+				idx[i] = i
+			}
+			sort.SliceStable(idx, func(i, j int) bool {
+				return ranks[idx[i]].Compare(ranks[idx[j]]) < 0
+			})
+			sorted := make([]Shot, len(typed))
+			for i, k := range idx {
+				sorted[i] = typed[k]
+			}
+			typed = sorted
+		} else {
+			sort.SliceStable(typed, func(i, j int) bool {
+				return r.Rank(typed[i].Cards[0].Num) < r.Rank(typed[j].Cards[0].Num)
+			})
+		}
+		shots = append(shots, typed...)
+	}
+	return append(shots, r.bombs(cards, cur)...)
+}
+
+// bombs lists the bombs of sorted cards that may be played on cur, from the
+// smallest to the largest.
+func (r *Rules) bombs(cards Cards, cur Shot) (shots []Shot) {
+	var candidates []Cards
+	collect := func(same Cards, min int) {
+		if min <= 0 {
+			return
+		}
+		for k := min; k <= len(same); k++ {
+			combos(same, k, func(c Cards) {
+				candidates = append(candidates, c.Copy())
+			})
+		}
+	}
+	var jokers Cards
+	for _, same := range runs(cards) {
+		collect(same, r.Bombs.MinOfAKind)
+		if same[0].IsJoker() {
+			jokers = append(jokers, same...)
+		}
+	}
+	collect(jokers, r.Bombs.MinJokers)
+	if r.Bombs.StraightFlush {
+		candidates = append(candidates, r.fiveCombos(cards)...)
+	}
+	var ranks []BombRank
+	seen := make(map[string]struct{})
+	for _, c := range candidates {
+		shot := Shot{
+			Cards: c,
+			Type:  ShotTypeBomb,
+		}
+		if _, ok := seen[c.String()]; ok || r.Beats(shot, cur) != nil {
+			continue
+		}
+		seen[c.String()] = struct{}{}
+		rank, _ := r.RankBomb(c)
+		shots = append(shots, shot)
+		ranks = append(ranks, rank)
+	}
+	idx := make([]int, len(shots))
+	for i := range idx {
+		idx[i] = i
+	}
+	sort.SliceStable(idx, func(i, j int) bool {
+		return ranks[idx[i]].Compare(ranks[idx[j]]) < 0
+	})
+	sorted := make([]Shot, len(shots))
+	for i, k := range idx {
+		sorted[i] = shots[k]
+	}
+	return sorted
+}
+
+// fiveCombos lists the distinct five-card combinations worth checking:
+// five of a kind, four plus one, full houses, straights and flushes.
+func (r *Rules) fiveCombos(cards Cards) (result []Cards) {
+	seen := make(map[string]struct{})
+	add := func(c Cards) {
+		c = c.Copy()
+		sort.Sort(CardSorter(c))
+		key := c.String()
+		if _, ok := seen[key]; ok {
+			return
+		}
+		seen[key] = struct{}{}
+		result = append(result, c)
+	}
+	groups := make(map[uint32]Cards)
+	var nums []uint32
+	for _, same := range runs(cards) {
+		groups[same[0].Num] = same
+		nums = append(nums, same[0].Num)
+	}
+
+	for _, num := range nums {
+		same := groups[num]
+		combos(same, 5, add)
+		combos(same, 4, func(four Cards) {
+			for _, other := range distinct(cards) {
+				if other.Num != num {
+					add(append(four.Copy(), other))
+				}
+			}
+		})
+		combos(same, 3, func(three Cards) {
+			for _, otherNum := range nums {
+				if otherNum == num {
+					continue
+				}
+				combos(groups[otherNum], 2, func(two Cards) {
+					add(append(three.Copy(), two...))
+				})
+			}
+		})
+	}
+	// straights
+	order := r.straightOrder()
+	for start := 0; start+5 <= len(order); start++ {
+		var choices []Cards
+		for _, num := range order[start : start+5] {
+			choices = append(choices, distinct(groups[num]))
+		}
+		var walk func(i int, picked Cards)
+		walk = func(i int, picked Cards) {
+			if i == len(choices) {
+				add(picked)
+				return
+			}
+			for _, card := range choices[i] {
+				walk(i+1, append(picked, card))
+			}
+		}
+		walk(0, nil)
+	}
+	// flushes
+	for _, color := range []CardColor{SPADE, HEART, CLUB, DIAMOND} {
+		var suited Cards
+		for _, card := range cards {
+			if card.Color == color {
+				suited = append(suited, card)
+			}
+		}
+		combos(suited, 5, add)
+	}
+	return
+}
+
+// straightOrder lists the numbers from the lowest to the highest rank as
+// straights run through them.
+func (r *Rules) straightOrder() []uint32 {
+	order := []uint32{3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15}
+	if !r.TwoHigh {
+		order = append([]uint32{15}, order[:12]...)
+	}
+	return order
+}
+
+// runs splits sorted cards into runs of the same number.
+func runs(cards Cards) (rs []Cards) {
+	for i, card := range cards {
+		if i == 0 || card.Num != cards[i-1].Num {
+			rs = append(rs, nil)
+		}
+		rs[len(rs)-1] = append(rs[len(rs)-1], card)
+	}
+	return
+}
+
+// distinct drops the repeated cards of sorted cards.
+func distinct(cards Cards) (rs Cards) {
+	for i, card := range cards {
+		if i == 0 || card != cards[i-1] {
+			rs = append(rs, card)
+		}
+	}
+	return
+}
+
+// combos calls fn with every distinct k-card combination of sorted cards.
+// The slice passed to fn is reused, fn must copy it to keep it.
+func combos(cards Cards, k int, fn func(Cards)) {
+	picked := make(Cards, 0, k)
+	var walk func(start int)
+	walk = func(start int) {
+		if len(picked) == k {
+			fn(picked)
+			return
+		}
+		for i := start; i <= len(cards)-(k-len(picked)); i++ {
+			if i > start && cards[i] == cards[i-1] {
+				continue
+			}
+			picked = append(picked, cards[i])
+			walk(i + 1)
+			picked = picked[:len(picked)-1]
+		}
+	}
+	walk(0)
+}
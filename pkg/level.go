@@ -0,0 +1,72 @@
+package pkg
+
+import "time"
+
+func init() {
+	RegisterStrategy("easy", func() Strategy { return EasyStrategy{Mistakes: 0.6} })
+	RegisterStrategy("hard", func() Strategy { return NewHardStrategy() })
+}
+
+// EasyStrategy is a gentle opponent for learning the game. It plays like
+// NormalStrategy, but with probability Mistakes it plays at random instead:
+// any of its legal shots, or a pass when it follows a round.
+type EasyStrategy struct {
+	Mistakes float64
+	// Seed makes the mistakes reproducible.
+	Seed int64
+}
+
+func (s EasyStrategy) NextShot(view TableView, curShot Shot) Shot {
+	rng := decisionRand(s.Seed, view)
+	if rng.Float64() >= s.Mistakes {
+		return NormalStrategy{}.NextShot(view, curShot)
+	}
+	shots := view.Rules.LegalShots(view.Hand, curShot)
+	if curShot.Type != ShotTypePass {
+		shots = append(shots, Shot{})
+	}
+	shot := shots[rng.Intn(len(shots))]
+	shot.Team = view.Team
+	return shot
+}
+
+// HardStrategy searches ahead with Search, which also weighs the shots of
+// PlanStrategy and CoopStrategy. Before searching it uses the cards it has
+// not seen: it goes out when it can, and when its plan has two shots left
+// and nobody can beat one of them it plays that one to lead the other.
+type HardStrategy struct {
+	Search *MCTSStrategy
+	// plan is the advisor of Search that keeps the plan of the hand.
+	plan *PlanStrategy
+}
+
+func NewHardStrategy() *HardStrategy {
+	plan := &PlanStrategy{}
+	search := NewMCTSStrategy()
+	search.Iterations = 60
+	search.Budget = time.Second
+	search.Advisors = []Strategy{plan, CoopStrategy{NearOut: 3}}
+	return &HardStrategy{
+		Search: search,
+		plan:   plan,
+	}
+}
+
+func (s *HardStrategy) NextShot(view TableView, curShot Shot) Shot {
+	if shot, err := view.ShotFor(view.Hand, curShot); err == nil {
+		return shot
+	}
+	if s.plan == nil {
+		s.plan = &PlanStrategy{}
+	}
+	if plan := s.plan.planOf(view); len(plan) == 2 {
+		tracker := view.Tracker()
+		for _, shot := range plan {
+			if view.Rules.Beats(shot, curShot) == nil && tracker.Unbeatable(shot) {
+				shot.Team = view.Team
+				return shot
+			}
+		}
+	}
+	return s.Search.NextShot(view, curShot)
+}
@@ -0,0 +1,190 @@
+package pkg
+
+import "sort"
+
+// Match plays hands with the same seats until a team reaches the target
+// score.
+//
+// A hand is won by the team whose players all go out first. The winners
+// score 3 points when they took the first three places, 2 when their last
+// player came fourth and 1 when it came fifth. Before the next deal the
+// losers pay as many tributes as the winners scored: the last placed loser
+// gives its highest card to the first placed winner, the next loser to the
+// next winner and so on, and every receiver gives a card back, its lowest
+// unless its strategy is a TributeReturner. The payer of the highest
+// tribute, the first of them on a tie, leads the next hand, or the previous
+// first place when nobody paid.
+type Match struct {
+	Game   Game
+	Target int
+	Scores map[uint32]int
+	Hands  []MatchHand
+}
+
+// MatchHand records a played hand with the tributes paid before it and
+// the points its winner scored.
+type MatchHand struct {
+	HandResult
+	Tributes []Tribute
+	Points   int
+}
+
+// Tribute is a card given by the seat From to the seat To, which gave the
+// card Return back.
+type Tribute struct {
+	From   int
+	To     int
+	Card   Card
+	Return Card
+}
+
+// TributeReturner is implemented by strategies that choose the card they
+// give back for a tribute.
+type TributeReturner interface {
+	ReturnTribute(view TableView, tribute Card) Card
+}
+
+func NewMatch(opts GameOptions, target int) *Match {
+	return &Match{
+		Game:   NewGame(opts),
+		Target: target,
+		Scores: make(map[uint32]int),
+	}
+}
+
+// HandPoints returns the team that won a hand and the points it scored,
+// given the seats from the first to the last place.
+func HandPoints(ranking []int, teams [6]uint32) (winner uint32, points int) {
+	placed := make(map[uint32]int)
+	for place, seat := range ranking {
+		team := teams[seat]
+		placed[team]++
+		if placed[team] == 3 {
+			return team, 6 - (place + 1)
+		}
+	}
+	return 0, 0
+}
+
+// Winner returns the first team that reached the target score, or 0.
+func (m *Match) Winner() uint32 {
+	var winner uint32
+	for team, score := range m.Scores {
+		if score >= m.Target && (winner == 0 || score > m.Scores[winner]) {
+			winner = team
+		}
+	}
+	return winner
+}
+
+// StartHand deals the next hand, exchanges the tributes owed from the
+// previous one and picks the leader.
+func (m *Match) StartHand() []Tribute {
+	g := &m.Game
+	if len(m.Hands) == 0 {
+		g.Start()
+		return nil
+	}
+	g.Deal()
+	last := m.Hands[len(m.Hands)-1]
+	var winners, losers []int
+	for _, seat := range last.Ranking {
+		if g.Players[seat].Team == last.Winner {
+			winners = append(winners, seat)
+		} else {
+			losers = append([]int{seat}, losers...)
+		}
+	}
+	leader := last.Ranking[0]
+	var tributes []Tribute
+	best := 0
+	for i := 0; i < last.Points && i < len(winners) && i < len(losers); i++ {
+		tribute := m.payTribute(losers[i], winners[i])
+		if len(tributes) == 0 || g.Rules.Rank(tribute.Card.Num) > g.Rules.Rank(tributes[best].Card.Num) {
+			best = len(tributes)
+			leader = tribute.From
+		}
+		tributes = append(tributes, tribute)
+	}
+	for i := range g.Players {
+		sort.Sort(CardSorter(g.Players[i].Cards))
+	}
+	g.SetLeader(leader)
+	return tributes
+}
+
+func (m *Match) payTribute(from, to int) Tribute {
+	g := &m.Game
+	payer, receiver := &g.Players[from], &g.Players[to]
+	card := extremeCard(&g.Rules, payer.Cards, true)
+	payer.RemoveCards(Cards{card})
+	receiver.AddCard(card)
+
+	back := extremeCard(&g.Rules, receiver.Cards, false)
+	if returner, ok := receiver.Strategy.(TributeReturner); ok {
+		chosen := returner.ReturnTribute(g.View(to), card)
+		if Cards(receiver.Cards).Contains(chosen) {
+			back = chosen
+		}
+	}
+	receiver.RemoveCards(Cards{back})
+	payer.AddCard(back)
+	return Tribute{
+		From:   from,
+		To:     to,
+		Card:   card,
+		Return: back,
+	}
+}
+
+// extremeCard returns the highest or the lowest ranked card of cards.
+func extremeCard(rules *Rules, cards Cards, highest bool) Card {
+	best := cards[0]
+	for _, card := range cards[1:] {
+		rank, bestRank := rules.Rank(card.Num), rules.Rank(best.Num)
+		if highest && rank > bestRank || !highest && rank < bestRank {
+			best = card
+		}
+	}
+	return best
+}
+
+// FinishHand scores the hand just played.
+func (m *Match) FinishHand() (MatchHand, error) {
+	result, err := m.Game.Result()
+	if err != nil {
+		return MatchHand{}, err
+	}
+	hand := MatchHand{
+		HandResult: result,
+	}
+	_, hand.Points = HandPoints(hand.Ranking, m.Game.Rules.Teams)
+	m.Scores[hand.Winner] += hand.Points
+	m.Hands = append(m.Hands, hand)
+	return hand, nil
+}
+
+// PlayHand plays a whole hand with the seats' strategies.
+func (m *Match) PlayHand() (MatchHand, error) {
+	tributes := m.StartHand()
+	if _, err := m.Game.Play(); err != nil {
+		return MatchHand{}, err
+	}
+	hand, err := m.FinishHand()
+	if err != nil {
+		return hand, err
+	}
+	hand.Tributes = tributes
+	m.Hands[len(m.Hands)-1] = hand
+	return hand, nil
+}
+
+// Play plays hands until a team reaches the target score and returns it.
+func (m *Match) Play() (uint32, error) {
+	for m.Winner() == 0 {
+		if _, err := m.PlayHand(); err != nil {
+			return 0, err
+		}
+	}
+	return m.Winner(), nil
+}
@@ -0,0 +1,339 @@
+package pkg
+
+import (
+	"hash/fnv"
+	"math"
+	"math/rand"
+	"sort"
+	"time"
+)
+
+func init() {
+	RegisterStrategy("mcts", func() Strategy { return NewMCTSStrategy() })
+}
+
+// MCTSStrategy picks its shot by information set Monte Carlo tree search.
+// Every iteration guesses the hidden cards, dealing the cards the seat has
+// neither in hand nor seen played among the other seats as many as each of
+// them holds, walks down the search tree choosing shots by UCB1, adds one
+// shot to the tree and plays the hand out with Rollout for every seat. The
+// outcome, the points of the hand for or against the team that chose the
+// shot, is added up along the walked path, and the most visited shot at the
+// root is played.
+//
+// The seat's own shots are taken from all legal shots, keeping the Width
+// smallest and the largest of every shot type and five-card category, the
+// other seats only choose between passing, Rollout's shot and the smallest
+// shots of their guessed hand.
+type MCTSStrategy struct {
+	// Iterations is the number of iterations per shot, no limit if zero
+	// and Budget is set, 100 if neither is set.
+	Iterations int
+	// Budget is the time allowed per shot, no limit if zero. It is wall
+	// clock time, so with a Budget the number of iterations, and then the
+	// shot, depends on the speed and load of the machine and Seed no longer
+	// reproduces the search.
+	Budget      time.Duration
+	Width       int
+	Exploration float64
+	// Rollout plays the hands out, NormalStrategy if nil.
+	Rollout Strategy
+	// Advisors suggest more shots for the seat to consider.
+	Advisors []Strategy
+	// Seed makes the search reproducible, see Budget.
+	Seed int64
+}
+
+func NewMCTSStrategy() *MCTSStrategy {
+	return &MCTSStrategy{
+		Iterations:  defaultIterations,
+		Budget:      time.Second,
+		Width:       3,
+		Exploration: 0.7,
+		Rollout:     NormalStrategy{},
+	}
+}
+
+const defaultIterations = 100
+
+// iterations returns the number of iterations per shot, zero for as many as
+// Budget allows.
+func (s *MCTSStrategy) iterations() int {
+	switch {
+	case s.Iterations > 0:
+		return s.Iterations
+	case s.Budget > 0:
+		return 0
+	}
+	return defaultIterations
+}
+
+func (s *MCTSStrategy) rollout() Strategy {
+	if s.Rollout == nil {
+		return NormalStrategy{}
+	}
+	return s.Rollout
+}
+
+// decisionRand returns the random numbers of a decision of the seat of
+// view. Seed is mixed with the seat's hand and the turn, so every decision
+// draws its own numbers and the same decision draws the same ones.
+func decisionRand(seed int64, view TableView) *rand.Rand {
+	h := fnv.New64a()
+	h.Write([]byte(view.Hand.String()))
+	return rand.New(rand.NewSource(seed ^ int64(h.Sum64()) ^ int64(view.Turn)))
+}
+
+type mctsNode struct {
+	shot     Shot
+	team     uint32
+	visits   int
+	wins     int
+	avail    int
+	reward   float64
+	children map[string]*mctsNode
+}
+
+func (s *MCTSStrategy) NextShot(view TableView, curShot Shot) Shot {
+	candidates := s.rootCandidates(view, curShot)
+	if len(candidates) == 1 {
+		return candidates[0]
+	}
+	rng := decisionRand(s.Seed, view)
+	root := &mctsNode{
+		children: make(map[string]*mctsNode),
+	}
+	start := time.Now()
+	iterations := s.iterations()
+	for i := 0; iterations == 0 || i < iterations; i++ {
+		if s.Budget > 0 && time.Since(start) > s.Budget {
+			break
+		}
+		s.iterate(rng, root, view, curShot, s.guessHands(rng, view), candidates)
+	}
+	var best *mctsNode
+	for _, shot := range candidates {
+		child := root.children[shotKey(shot)]
+		if child != nil && (best == nil || child.visits > best.visits) {
+			best = child
+		}
+	}
+	if best == nil {
+		return candidates[0]
+	}
+	return best.shot
+}
+
+// WinRates estimates for each of shots how likely the seat's team wins the
+// hand if the seat plays it, searching on from every shot as NextShot does:
+// Iterations times it guesses the hidden cards and, on the same guess, plays
+// every shot at the root of one search tree and walks down the tree below
+// it. The rate of a shot is how often its team won in the iterations below
+// it.
+func (s *MCTSStrategy) WinRates(view TableView, curShot Shot, shots []Shot) []float64 {
+	rng := decisionRand(s.Seed, view)
+	root := &mctsNode{
+		children: make(map[string]*mctsNode),
+	}
+	iterations := s.Iterations
+	if iterations <= 0 {
+		iterations = defaultIterations
+	}
+	for i := 0; i < iterations; i++ {
+		hands := s.guessHands(rng, view)
+		for _, shot := range shots {
+			s.iterate(rng, root, view, curShot, hands, []Shot{shot})
+		}
+	}
+	rates := make([]float64, len(shots))
+	for k, shot := range shots {
+		if child := root.children[shotKey(shot)]; child != nil && child.visits > 0 {
+			rates[k] = float64(child.wins) / float64(child.visits)
+		}
+	}
+	return rates
+}
+
+// iterate walks down the tree from root on the guessed hands, choosing the
+// seat's shot at the root among candidates.
+func (s *MCTSStrategy) iterate(rng *rand.Rand, root *mctsNode, view TableView, curShot Shot, hands [6]Cards, candidates []Shot) {
+	g := NewGameAt(view, curShot, hands)
+	for i := range g.Players {
+		g.Players[i].Strategy = s.rollout()
+	}
+	path := []*mctsNode{root}
+	node := root
+	for !g.IsFinished() {
+		seat := g.CurrentPlayer()
+		shots := candidates
+		if node != root {
+			shots = s.candidates(&g, seat)
+		}
+		var untried []Shot
+		var tried []*mctsNode
+		for _, shot := range shots {
+			if child := node.children[shotKey(shot)]; child != nil {
+				child.avail++
+				tried = append(tried, child)
+			} else {
+				untried = append(untried, shot)
+			}
+		}
+		var child *mctsNode
+		if len(untried) != 0 {
+			shot := untried[rng.Intn(len(untried))]
+			child = &mctsNode{
+				shot:     shot,
+				team:     g.Players[seat].Team,
+				avail:    1,
+				children: make(map[string]*mctsNode),
+			}
+			node.children[shotKey(shot)] = child
+		} else {
+			child = s.selectUCB(tried)
+		}
+		if err := g.Apply(seat, child.shot); err != nil {
+			break
+		}
+		path = append(path, child)
+		node = child
+		if len(untried) != 0 {
+			break
+		}
+	}
+	if !g.IsFinished() {
+		if _, err := g.Play(); err != nil {
+			return
+		}
+	}
+	result, err := g.Result()
+	if err != nil {
+		return
+	}
+	winner, points := HandPoints(result.Ranking, g.Rules.Teams)
+	for _, n := range path {
+		n.visits++
+		if n.team == winner {
+			n.wins++
+			n.reward += 0.5 + float64(points)/6
+		} else {
+			n.reward += 0.5 - float64(points)/6
+		}
+	}
+}
+
+func (s *MCTSStrategy) selectUCB(children []*mctsNode) (best *mctsNode) {
+	bestScore := math.Inf(-1)
+	for _, child := range children {
+		score := child.reward/float64(child.visits) +
+			s.Exploration*math.Sqrt(math.Log(float64(child.avail))/float64(child.visits))
+		if score > bestScore {
+			best, bestScore = child, score
+		}
+	}
+	return
+}
+
+// guessHands deals the cards view's seat cannot see to the other seats.
+func (s *MCTSStrategy) guessHands(rng *rand.Rand, view TableView) (hands [6]Cards) {
+	unseen := view.Tracker().UnseenCards()
+	rng.Shuffle(len(unseen), func(i, j int) {
+		unseen[i], unseen[j] = unseen[j], unseen[i]
+	})
+	for i := range hands {
+		if i == view.Seat {
+			hands[i] = view.Hand.Copy()
+			continue
+		}
+		n := view.CardCounts[i]
+		if n > len(unseen) {
+			n = len(unseen)
+		}
+		hands[i] = unseen[:n:n]
+		unseen = unseen[n:]
+	}
+	return
+}
+
+// rootCandidates lists the shots the seat considers: a pass when it may
+// pass, the shots of Rollout and Advisors, and of every shot type and
+// five-card category the Width smallest shots and the largest.
+func (s *MCTSStrategy) rootCandidates(view TableView, curShot Shot) []Shot {
+	var shots []Shot
+	if curShot.Type != ShotTypePass {
+		shots = append(shots, Shot{})
+	}
+	shots = append(shots, s.rollout().NextShot(view, curShot))
+	for _, advisor := range s.Advisors {
+		shots = append(shots, advisor.NextShot(view, curShot))
+	}
+	legal := view.Rules.LegalShots(view.Hand, curShot)
+	bucket := func(shot Shot) int {
+		if shot.Type == ShotTypeFive {
+			rank, _ := view.Rules.RankFive(shot.Cards)
+			return 100 + int(rank.Category)
+		}
+		return int(shot.Type)
+	}
+	start := 0
+	for i, shot := range legal {
+		if bucket(shot) != bucket(legal[start]) {
+			start = i
+		}
+		largest := i == len(legal)-1 || bucket(legal[i+1]) != bucket(shot)
+		if i-start < s.Width || largest {
+			shots = append(shots, shot)
+		}
+	}
+	return distinctShots(shots, view.Team)
+}
+
+// candidates lists the shots the search tries for another seat: a pass,
+// Rollout's shot, and the smallest shot that follows the round or the
+// smallest single, pair and triple to lead a round.
+func (s *MCTSStrategy) candidates(g *Game, seat int) []Shot {
+	view := g.View(seat)
+	curShot := g.CurrentShot()
+	var shots []Shot
+	if curShot.Type != ShotTypePass {
+		shots = append(shots, Shot{})
+	}
+	shots = append(shots, s.rollout().NextShot(view, curShot))
+	if curShot.Type == ShotTypePass {
+		groups := view.Hand.Groups()
+		for _, groups := range groups[:3] {
+			if len(groups) == 0 {
+				continue
+			}
+			if t, err := view.Rules.ShotTypeOf(groups[0]); err == nil && t != ShotTypeBomb {
+				shots = append(shots, Shot{
+					Cards: groups[0],
+					Type:  t,
+				})
+			}
+		}
+	} else if curShot.Type != ShotTypeFive {
+		if legal := view.Rules.LegalShots(view.Hand, curShot); len(legal) != 0 {
+			shots = append(shots, legal[0])
+		}
+	}
+	return distinctShots(shots, view.Team)
+}
+
+func distinctShots(shots []Shot, team uint32) (distinct []Shot) {
+	seen := make(map[string]bool)
+	for _, shot := range shots {
+		if key := shotKey(shot); !seen[key] {
+			seen[key] = true
+			shot.Team = team
+			distinct = append(distinct, shot)
+		}
+	}
+	return
+}
+
+func shotKey(shot Shot) string {
+	cards := shot.Cards.Copy()
+	sort.Sort(CardSorter(cards))
+	return string(rune('0'+shot.Type)) + cards.String()
+}
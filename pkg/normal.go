@@ -0,0 +1,126 @@
+package pkg
+
+func init() {
+	RegisterStrategy("normal", func() Strategy { return NormalStrategy{} })
+}
+
+// NormalStrategy leads its five-card combos first and then its smallest
+// groups, and lets a teammate's shot through unless it is a small one.
+type NormalStrategy struct{}
+
+func (s NormalStrategy) NextShot(view TableView, curShot Shot) Shot {
+	p := &Player{
+		Cards: view.Hand.Copy(),
+		Team:  view.Team,
+	}
+	if curShot.Type == ShotTypePass {
+		return s.newRoundShot(&view.Rules, p)
+	} else if s.checkFriendShot(&view.Rules, p, curShot) {
+		return Shot{
+			Team: p.Team,
+		}
+	} else {
+		return s.shotByType(&view.Rules, p, curShot)
+	}
+}
+
+func (s NormalStrategy) checkFriendShot(rules *Rules, p *Player, curShot Shot) bool {
+	if curShot.Team != p.Team {
+		return false
+	}
+	cards := curShot.Cards
+	if curShot.Type == ShotTypeBomb {
+		return true
+	}
+	if curShot.Type < 5 {
+		rank := rules.Rank(cards[0].Num)
+		return !(3 <= rank && rank <= rules.FriendPassMax)
+	}
+	rank, err := rules.RankFive(cards)
+	if err != nil {
+		panic(err)
+	}
+	switch rank.Category {
+	case FiveCardStraight, FiveCardFlush, FiveCardFullHouse:
+		return false
+	case FiveCardFourOfAKind:
+		return !(3 <= rank.Keys[0] && rank.Keys[0] <= rules.FriendPassMax)
+	default:
+		return true
+	}
+}
+
+func (s NormalStrategy) shotByType(rules *Rules, p *Player, curShot Shot) Shot {
+	if curShot.Type == ShotTypeFive {
+		cards5Combos := p.FormFive()
+		for _, cards := range cards5Combos {
+			if len(cards) != 5 {
+				break
+			}
+			shot := Shot{
+				Cards: cards,
+				Type:  5,
+				Team:  p.Team,
+			}
+			if rules.Beats(shot, curShot) == nil {
+				return shot
+			}
+		}
+	} else {
+		// type 1, 2, 3
+		for cardType, groups := range Cards(p.Cards).Groups() {
+			if ShotType(cardType+1) != curShot.Type {
+				continue
+			}
+			for _, cards := range groups {
+				shot := Shot{
+					Cards: cards,
+					Type:  ShotType(len(cards)),
+					Team:  p.Team,
+				}
+				if rules.Beats(shot, curShot) == nil {
+					return shot
+				}
+			}
+		}
+	}
+	return Shot{
+		Team: p.Team,
+	}
+}
+
+func (s NormalStrategy) newRoundShot(rules *Rules, p *Player) Shot {
+	// type 5
+	for _, cards := range p.FormFive() {
+		if len(cards) != 5 {
+			break
+		}
+		if t, err := rules.ShotTypeOf(cards); err == nil && t == ShotTypeFive {
+			return Shot{
+				Cards: cards,
+				Type:  5,
+				Team:  p.Team,
+			}
+		}
+	}
+	// type 1, 2, 3
+	for _, groups := range Cards(p.Cards).Groups() {
+		for _, cards := range groups {
+			for n := len(cards); n > 0; n-- {
+				if t, err := rules.ShotTypeOf(cards[:n]); err == nil && t != ShotTypeBomb {
+					return Shot{
+						Cards: cards[:n],
+						Type:  ShotType(n),
+						Team:  p.Team,
+					}
+				}
+			}
+		}
+	}
+	shots := rules.LegalShots(p.Cards, Shot{})
+	if len(shots) == 0 {
+		panic("newRoundShot() panic")
+	}
+	shots[0].Team = p.Team
+	return shots[0]
+}
@@ -0,0 +1,574 @@
+package pkg
+
+import "sort"
+
+func init() {
+	RegisterStrategy("planner", func() Strategy { return &PlanStrategy{} })
+}
+
+// PlanHand splits hand into the fewest shots that can be led one after
+// another, using five-card combinations of every kind wherever they save
+// shots. The plan is ordered like LegalShots: by type and, within a type,
+// from the smallest to the largest.
+//
+// Every branch of the search takes the lowest number left and either plays
+// its cards as equal-card groups, or puts one of them in a five-card
+// combination or in a flush with four higher cards of its suit, so every
+// partition is reachable. Suits are only told apart while they can still
+// make a flush, and branches that cannot beat the best plan found so far
+// are cut.
+func (r *Rules) PlanHand(hand Cards) []Shot {
+	plan := newPlanner(r).planCards(hand)
+	r.sortPlan(plan)
+	return plan
+}
+
+// sortPlan orders plan like PlanHand.
+func (r *Rules) sortPlan(plan []Shot) {
+	sort.SliceStable(plan, func(i, j int) bool {
+		if plan[i].Type != plan[j].Type {
+			return plan[i].Type < plan[j].Type
+		}
+		cmp, _ := r.Compare(plan[i].Cards, plan[j].Cards)
+		return cmp < 0
+	})
+}
+
+// suits are the suits in the order the planner tries their flushes.
+var suits = []CardColor{SPADE, HEART, CLUB, DIAMOND}
+
+type planner struct {
+	rules   *Rules
+	memo    map[string]planEntry
+	valids  map[string]bool
+	flushes map[string][][]uint32
+	// kinds holds the four of a kind and full house of every two numbers,
+	// see kind, and straights the straights of straightOrder by the index
+	// of their lowest number.
+	kinds     [23][23][2]plannedFive
+	straights []plannedFive
+	order     []uint32
+}
+
+// plannedFive is a five-card combination the planner looked up, nil if the
+// numbers make none.
+type plannedFive struct {
+	nums []uint32
+	done bool
+}
+
+// planShot is a shot of a plan by its numbers. A flush has a suit, the
+// other shots may take the cards of any suit.
+type planShot struct {
+	nums []uint32
+	suit CardColor
+}
+
+func newPlanner(rules *Rules) *planner {
+	p := &planner{
+		rules:   rules,
+		memo:    make(map[string]planEntry),
+		valids:  make(map[string]bool),
+		flushes: make(map[string][][]uint32),
+	}
+	p.order = rules.straightOrder()
+	p.straights = make([]plannedFive, len(p.order))
+	return p
+}
+
+// planCards plans cards. The planner works on cards sorted by rank, see
+// Rules.Rank, so that the lowest number left is the lowest ranked one.
+func (p *planner) planCards(cards Cards) []Shot {
+	cards = cards.Copy()
+	sort.Sort(CardSorter(cards))
+	sort.SliceStable(cards, func(i, j int) bool {
+		return p.rules.Rank(cards[i].Num) < p.rules.Rank(cards[j].Num)
+	})
+	suited := make([][]uint32, len(suits))
+	for _, card := range cards {
+		for i, suit := range suits {
+			if card.Color == suit {
+				suited[i] = append(suited[i], card.Num)
+			}
+		}
+	}
+	plan, _ := p.plan(nums(cards), suited, len(cards)+1)
+	return p.realize(cards, plan)
+}
+
+// plan returns the fewest shots of numbers sorted by rank if they are fewer
+// than limit. The numbers of suited[i] are those of the cards of suits[i]
+// that no flush took yet, a flush takes its numbers from both.
+func (p *planner) plan(left []uint32, suited [][]uint32, limit int) ([]planShot, bool) {
+	if len(left) == 0 {
+		return nil, limit > 0
+	}
+	if p.lowerBound(left) >= limit {
+		return nil, false
+	}
+	suited = p.flushable(left, suited)
+	key := make([]byte, 0, 2*len(left)+len(suited))
+	key = append(key, numsKey(left)...)
+	for _, same := range suited {
+		key = append(key, 0)
+		for _, num := range same {
+			key = append(key, byte(num))
+		}
+	}
+	if entry, ok := p.memo[string(key)]; ok {
+		if entry.exact {
+			return entry.plan, len(entry.plan) < limit
+		}
+		if entry.bound >= limit {
+			return nil, false
+		}
+	}
+	var best []planShot
+	found := false
+	// try keeps head and the plan of the rest if they beat the best
+	try := func(head []planShot, rest []uint32, suited [][]uint32) {
+		if plan, ok := p.plan(rest, suited, limit-len(head)); ok {
+			best = append(head, plan...)
+			found, limit = true, len(best)
+		}
+	}
+	n := 1
+	for n < len(left) && left[n] == left[0] {
+		n++
+	}
+	if p.rules.Rank(left[0]) >= p.rules.Rank(21) {
+		var jokers []planShot
+		for _, shot := range p.jokers(left) {
+			jokers = append(jokers, planShot{nums: shot})
+		}
+		if len(jokers) < limit {
+			best, found = jokers, true
+		}
+	} else {
+		var groups []planShot
+		for _, shot := range p.groups(left[:n]) {
+			groups = append(groups, planShot{nums: shot})
+		}
+		try(groups, left[n:], suited)
+		for _, five := range p.fivesWith(left) {
+			try([]planShot{{nums: five}}, withoutNums(left, five), suited)
+		}
+		for i, suit := range suits {
+			for _, flush := range p.flushesWith(suit, suited[i]) {
+				if flush[0] != left[0] {
+					break
+				}
+				rest := append([][]uint32(nil), suited...)
+				rest[i] = withoutNums(suited[i], flush)
+				try([]planShot{{nums: flush, suit: suit}}, withoutNums(left, flush), rest)
+			}
+		}
+	}
+	if found {
+		p.memo[string(key)] = planEntry{plan: best, exact: true}
+	} else {
+		p.memo[string(key)] = planEntry{bound: limit}
+	}
+	return best, found
+}
+
+// planEntry is what the planner learned of some numbers: their plan if
+// exact, or else that they need at least bound shots.
+type planEntry struct {
+	plan  []planShot
+	exact bool
+	bound int
+}
+
+// lowerBound returns the least number of shots sorted numbers need: no shot
+// is larger than five cards or than the longest run of a number or of
+// jokers.
+func (p *planner) lowerBound(left []uint32) int {
+	size, run, jokers := 5, 0, 0
+	for i, num := range left {
+		if i > 0 && num == left[i-1] {
+			run++
+		} else {
+			run = 1
+		}
+		if num >= 21 {
+			jokers++
+		}
+		if run > size {
+			size = run
+		}
+		if jokers > size {
+			size = jokers
+		}
+	}
+	return (len(left) + size - 1) / size
+}
+
+// flushable keeps the numbers of suited that the numbers left still have,
+// and only for the suits that can still make a flush.
+func (p *planner) flushable(left []uint32, suited [][]uint32) [][]uint32 {
+	kept := make([][]uint32, len(suited))
+	size := 0
+	for _, same := range suited {
+		size += len(same)
+	}
+	buf := make([]uint32, 0, size)
+	for i, same := range suited {
+		if len(same) < 5 {
+			continue
+		}
+		kept[i] = buf[len(buf):len(buf)]
+		var distinct int
+		for j, k := 0, 0; j < len(same) && k < len(left); {
+			switch a, b := p.rules.Rank(same[j]), p.rules.Rank(left[k]); {
+			case a < b:
+				j++
+			case a > b:
+				k++
+			default:
+				if len(kept[i]) == 0 || kept[i][len(kept[i])-1] != same[j] {
+					distinct++
+				}
+				kept[i] = append(kept[i], same[j])
+				j++
+				k++
+			}
+		}
+		if distinct < 5 {
+			kept[i] = nil
+			continue
+		}
+		kept[i] = kept[i][:len(kept[i]):len(kept[i])]
+		buf = buf[:len(buf)+len(kept[i])]
+	}
+	return kept
+}
+
+// groups splits equal numbers into the fewest shots.
+func (p *planner) groups(same []uint32) (shots [][]uint32) {
+	for len(same) != 0 {
+		n := len(same)
+		for ; n > 1 && !p.valid(same[:n]); n-- {
+		}
+		shots = append(shots, same[:n])
+		same = same[n:]
+	}
+	return
+}
+
+// jokers plays the jokers left at the end of the numbers as one bomb, or
+// else by their numbers.
+func (p *planner) jokers(jokers []uint32) [][]uint32 {
+	if p.valid(jokers) {
+		return [][]uint32{jokers}
+	}
+	n := 1
+	for n < len(jokers) && jokers[n] == jokers[0] {
+		n++
+	}
+	return append(p.groups(jokers[:n]), p.groups(jokers[n:])...)
+}
+
+// fivesWith lists the five-card combinations of numbers sorted by rank that
+// hold the lowest number.
+func (p *planner) fivesWith(left []uint32) (fives [][]uint32) {
+	var count [23]int
+	for _, num := range left {
+		count[num]++
+	}
+	add := func(a uint32, n int, b uint32, m int) {
+		if count[a] >= n && count[b] >= m {
+			if five := p.kind(a, n, b); five != nil {
+				fives = append(fives, five)
+			}
+		}
+	}
+	low := left[0]
+	for i, other := range left {
+		if other == low || other == left[i-1] {
+			continue
+		}
+		add(low, 4, other, 1)
+		add(other, 4, low, 1)
+		add(low, 3, other, 2)
+		add(other, 3, low, 2)
+	}
+	for start := 0; start+5 <= len(p.order); start++ {
+		window := p.order[start : start+5]
+		has, all := false, true
+		for _, num := range window {
+			has = has || num == low
+			all = all && count[num] != 0
+		}
+		if has && all {
+			five := &p.straights[start]
+			if !five.done {
+				five.done = true
+				if p.valid(window) {
+					five.nums = window
+				}
+			}
+			if five.nums != nil {
+				fives = append(fives, five.nums)
+			}
+		}
+	}
+	return
+}
+
+// kind returns n cards of number a and 5-n of number b if they make a four
+// of a kind or a full house, else nil.
+func (p *planner) kind(a uint32, n int, b uint32) []uint32 {
+	five := &p.kinds[a][b][n-3]
+	if !five.done {
+		five.done = true
+		nums := make([]uint32, 0, 5)
+		for i := 0; i < 5; i++ {
+			if i < n {
+				nums = append(nums, a)
+			} else {
+				nums = append(nums, b)
+			}
+		}
+		if p.valid(nums) {
+			five.nums = nums
+		}
+	}
+	return five.nums
+}
+
+// flushesWith lists the flushes of suit of numbers same, sorted by rank,
+// that hold the lowest number.
+func (p *planner) flushesWith(suit CardColor, same []uint32) (flushes [][]uint32) {
+	var distinct []uint32
+	for _, num := range same {
+		if len(distinct) == 0 || distinct[len(distinct)-1] != num {
+			distinct = append(distinct, num)
+		}
+	}
+	key := string(append(numsKey(distinct), byte(mapColorOrder[suit])))
+	if flushes, ok := p.flushes[key]; ok {
+		return flushes
+	}
+	defer func() { p.flushes[key] = flushes }()
+	flush := make([]uint32, 1, 5)
+	var pick func(from int)
+	pick = func(from int) {
+		if len(flush) == cap(flush) {
+			cards := make(Cards, len(flush))
+			for i, num := range flush {
+				cards[i] = Card{Num: num, Color: suit}
+			}
+			if _, err := p.rules.ShotTypeOf(cards); err == nil {
+				flushes = append(flushes, append([]uint32(nil), flush...))
+			}
+			return
+		}
+		for i := from; i+cap(flush)-len(flush) <= len(distinct); i++ {
+			flush = append(flush, distinct[i])
+			pick(i + 1)
+			flush = flush[:len(flush)-1]
+		}
+	}
+	if len(distinct) >= cap(flush) {
+		flush[0] = distinct[0]
+		pick(1)
+	}
+	return
+}
+
+// valid reports whether cards of the numbers, in mixed suits, make a shot.
+func (p *planner) valid(shot []uint32) bool {
+	key := numsKey(shot)
+	valid, ok := p.valids[string(key)]
+	if !ok {
+		_, err := p.rules.ShotTypeOf(p.cards(shot))
+		valid = err == nil
+		p.valids[string(key)] = valid
+	}
+	return valid
+}
+
+func (p *planner) cards(shot []uint32) Cards {
+	cards := make(Cards, len(shot))
+	for i, num := range shot {
+		cards[i] = Card{Num: num}
+		if p.rules.Rank(num) < p.rules.Rank(21) {
+			cards[i].Color = suits[i%len(suits)]
+		}
+	}
+	return cards
+}
+
+// realize picks the cards of a plan from cards, those of the flushes
+// first.
+func (p *planner) realize(cards Cards, plan []planShot) []Shot {
+	shots := make([]Shot, len(plan))
+	rest := cards.Copy()
+	for _, flushes := range []bool{true, false} {
+		for k, planned := range plan {
+			if (planned.suit != "") != flushes {
+				continue
+			}
+			var picked Cards
+			for _, num := range planned.nums {
+				for i, card := range rest {
+					if card.Num == num && (planned.suit == "" || card.Color == planned.suit) {
+						picked = append(picked, card)
+						rest = append(rest[:i], rest[i+1:]...)
+						break
+					}
+				}
+			}
+			shot, err := p.rules.ShotFor(picked, Shot{})
+			if err != nil {
+				panic(err)
+			}
+			shots[k] = shot
+		}
+	}
+	return shots
+}
+
+func nums(cards Cards) []uint32 {
+	nums := make([]uint32, len(cards))
+	for i, card := range cards {
+		nums[i] = card.Num
+	}
+	return nums
+}
+
+func numsKey(nums []uint32) []byte {
+	key := make([]byte, len(nums))
+	for i, num := range nums {
+		key[i] = byte(num)
+	}
+	return key
+}
+
+// withoutNums returns numbers without the numbers of shot, keeping their
+// order.
+func withoutNums(left []uint32, shot []uint32) []uint32 {
+	rest := append([]uint32(nil), left...)
+	for _, num := range shot {
+		for i := range rest {
+			if rest[i] == num {
+				rest = append(rest[:i], rest[i+1:]...)
+				break
+			}
+		}
+	}
+	return rest
+}
+
+// without returns sorted cards without the cards of shot.
+func without(cards Cards, shot Cards) Cards {
+	rest := cards.Copy()
+	for _, card := range shot {
+		for i := range rest {
+			if rest[i] == card {
+				rest = append(rest[:i], rest[i+1:]...)
+				break
+			}
+		}
+	}
+	return rest
+}
+
+// PlanStrategy plays its hand by the plan of PlanHand. It leads the
+// smallest shot of its plan, saving bombs for the end, and follows with the
+// shot that leaves the fewest shots to play, letting a teammate's shot
+// through like NormalStrategy.
+//
+// The plan is kept between turns: some shots of a plan of the fewest shots
+// are still the fewest shots of their cards, so it only plans again when
+// the hand is no longer made of shots of the plan.
+type PlanStrategy struct {
+	plan []Shot
+}
+
+func (s *PlanStrategy) NextShot(view TableView, curShot Shot) Shot {
+	rules := &view.Rules
+	plan := s.planOf(view)
+	if curShot.Type == ShotTypePass {
+		shot := plan[0]
+		for _, planned := range plan {
+			if planned.Type != ShotTypeBomb {
+				shot = planned
+				break
+			}
+		}
+		shot.Team = view.Team
+		return shot
+	}
+	p := &Player{
+		Cards: view.Hand,
+		Team:  view.Team,
+	}
+	if (NormalStrategy{}).checkFriendShot(rules, p, curShot) {
+		return Shot{Team: view.Team}
+	}
+	for _, shot := range plan {
+		if rules.Beats(shot, curShot) == nil && shot.Type != ShotTypeBomb {
+			shot.Team = view.Team
+			return shot
+		}
+	}
+	// breaking the plan may cost at most one more shot, the plan of the
+	// rest is kept for the next turns
+	best, bestLen, bestPlan := Shot{Team: view.Team}, len(plan)+1, plan
+	hand := view.Hand.Copy()
+	sort.Sort(CardSorter(hand))
+	planner := newPlanner(rules)
+	tried := make(map[string]bool)
+	for _, shot := range rules.LegalShots(hand, curShot) {
+		key := string(numsKey(nums(shot.Cards)))
+		if shot.Type == ShotTypeBomb || tried[key] {
+			continue
+		}
+		tried[key] = true
+		rest := planner.planCards(without(hand, shot.Cards))
+		if n := len(rest) + 1; n < bestLen || (n == bestLen && best.Type == ShotTypePass) {
+			best, bestLen, bestPlan = shot, n, rest
+		}
+		// no shot leaves fewer shots than the plan
+		if bestLen == len(plan) {
+			break
+		}
+	}
+	rules.sortPlan(bestPlan)
+	s.plan = bestPlan
+	best.Team = view.Team
+	return best
+}
+
+// planOf returns the plan of the seat's hand, the shots of the kept plan
+// that make up the hand if there are any.
+func (s *PlanStrategy) planOf(view TableView) []Shot {
+	rest := view.Hand
+	var kept []Shot
+	for _, shot := range s.plan {
+		if left, ok := takeCards(rest, shot.Cards); ok {
+			kept, rest = append(kept, shot), left
+		}
+	}
+	if len(kept) == 0 || len(rest) != 0 {
+		kept = view.Rules.PlanHand(view.Hand)
+	}
+	s.plan = kept
+	return kept
+}
+
+// takeCards returns cards without the cards of shot, failing unless cards
+// hold all of them.
+func takeCards(cards Cards, shot Cards) (Cards, bool) {
+	rest := cards.Copy()
+	for _, card := range shot {
+		i := rest.index(card, true)
+		if i < 0 {
+			return cards, false
+		}
+		rest = append(rest[:i], rest[i+1:]...)
+	}
+	return rest, true
+}
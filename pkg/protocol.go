@@ -0,0 +1,187 @@
+package pkg
+
+import (
+	"encoding/json"
+	"io"
+	"sync"
+)
+
+// The table protocol spoken by pkg/server runs over a stream connection,
+// e.g. TCP. Every line is a Message, a JSON object with the message type and
+// its data, if any. Cards are written as by Cards.String.
+//
+// A client first asks for a seat with join and gets seated or an error:
+//
+//	-> {"type":"join","data":{"name":"ann","team":2}}
+//	<- {"type":"seated","data":{"seat":1,"team":2}}
+//
+// The match starts when all six seats are taken or a seated client sends
+// start; seats nobody joined are played by the server's AI. Every hand
+// begins with deal, which holds the receiver's own hand only:
+//
+//	-> {"type":"start"}
+//	<- {"type":"deal","data":{"rules":{...},"seat":1,"hand":"S3 H3 ...","leader":4,"names":[...],"teams":[1,2,1,2,1,2]}}
+//
+// All clients see every shot, pass, new round and finished player:
+//
+//	<- {"type":"played","data":{"seat":4,"turn":1,"shot":{"cards":"S5","type":1,"team":1}}}
+//	<- {"type":"passed","data":{"seat":5,"turn":2}}
+//	<- {"type":"round","data":{"leader":4}}
+//	<- {"type":"finished","data":{"seat":4,"turn":80,"place":1}}
+//
+// When it is the client's turn it gets your-turn with its hand, the shot to
+// beat, which has type 0 when it leads a new round, and whether that shot is
+// its teammate's. It answers with play or pass; a rejected answer gets an
+// error and the same turn continues, so the client answers again:
+//
+//	<- {"type":"your-turn","data":{"hand":"S3 H3 ...","shot":{"cards":"S5","type":1,"team":1},"friend":false,"cardCounts":[26,27,27,27,27,27]}}
+//	-> {"type":"play","data":{"cards":"H3"}}
+//	<- {"type":"error","data":{"error":"..."}}
+//	-> {"type":"pass"}
+//
+// A client that takes longer than the server's turn time for its turn, or
+// to read its messages, gets an error and is disconnected, and the AI plays
+// its seat from then on. Without a turn time reading a message may still
+// take at most server.MaxWriteTime.
+//
+// Every hand ends with hand-over, which tells whether the match is over:
+//
+//	<- {"type":"hand-over","data":{"result":{...},"points":2,"scores":{"1":0,"2":2},"over":true}}
+//
+// Messages a client sends at the wrong time, such as play out of its turn,
+// are answered with an error and otherwise ignored.
+const (
+	MsgJoin       = "join"
+	MsgStart      = "start"
+	MsgPlay       = "play"
+	MsgPass       = "pass"
+	MsgSeated     = "seated"
+	MsgDeal       = "deal"
+	MsgTurn       = "your-turn"
+	MsgPlayed     = "played"
+	MsgPassed     = "passed"
+	MsgRoundReset = "round"
+	MsgFinished   = "finished"
+	MsgHandOver   = "hand-over"
+	MsgError      = "error"
+)
+
+// Message is a line of the table protocol: a JSON object with the message
+// type and its data, e.g. {"type":"play","data":{"cards":"S3 H3"}}.
+type Message struct {
+	Type string          `json:"type"`
+	Data json.RawMessage `json:"data,omitempty"`
+}
+
+// Decode reads the data of the message into v.
+func (m Message) Decode(v interface{}) error {
+	if len(m.Data) == 0 {
+		return nil
+	}
+	return json.Unmarshal(m.Data, v)
+}
+
+// Join asks for a seat. Seat picks one, Team the first free seat of a team,
+// and without both any free seat is taken.
+type Join struct {
+	Name string `json:"name"`
+	Seat *int   `json:"seat,omitempty"`
+	Team uint32 `json:"team,omitempty"`
+}
+
+type Seated struct {
+	Seat int    `json:"seat"`
+	Team uint32 `json:"team"`
+}
+
+// Deal starts a hand. Hand is the receiver's own hand only.
+type Deal struct {
+	Rules  Rules     `json:"rules"`
+	Seat   int       `json:"seat"`
+	Hand   Cards     `json:"hand"`
+	Leader int       `json:"leader"`
+	Names  [6]string `json:"names"`
+	Teams  [6]uint32 `json:"teams"`
+}
+
+// Turn asks the receiver for its shot on Shot, which is a pass when it
+// leads a new round. Friend is set when Shot was played by its team.
+type Turn struct {
+	Hand       Cards  `json:"hand"`
+	Shot       Shot   `json:"shot"`
+	Friend     bool   `json:"friend"`
+	CardCounts [6]int `json:"cardCounts"`
+}
+
+// Play plays cards, written as by Cards.String. Cards without a suit stand
+// for any card of their number in the hand.
+type Play struct {
+	Cards Cards `json:"cards"`
+}
+
+type Played struct {
+	Seat int  `json:"seat"`
+	Turn int  `json:"turn"`
+	Shot Shot `json:"shot"`
+}
+
+type PassedMsg struct {
+	Seat int `json:"seat"`
+	Turn int `json:"turn"`
+}
+
+type RoundResetMsg struct {
+	Leader int `json:"leader"`
+}
+
+type FinishedMsg struct {
+	Finish
+	Place int `json:"place"`
+}
+
+type HandOverMsg struct {
+	Result HandResult     `json:"result"`
+	Points int            `json:"points"`
+	Scores map[uint32]int `json:"scores"`
+	Over   bool           `json:"over"`
+}
+
+type ErrorMsg struct {
+	Error string `json:"error"`
+}
+
+// MessageConn reads and writes protocol messages, one per line. Send may be
+// called from several goroutines.
+type MessageConn struct {
+	mu  sync.Mutex
+	enc *json.Encoder
+	dec *json.Decoder
+}
+
+func NewMessageConn(rw io.ReadWriter) *MessageConn {
+	return &MessageConn{
+		enc: json.NewEncoder(rw),
+		dec: json.NewDecoder(rw),
+	}
+}
+
+func (c *MessageConn) Send(typ string, data interface{}) error {
+	msg := Message{
+		Type: typ,
+	}
+	if data != nil {
+		raw, err := json.Marshal(data)
+		if err != nil {
+			return err
+		}
+		msg.Data = raw
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.enc.Encode(msg)
+}
+
+func (c *MessageConn) Receive() (msg Message, err error) {
+	err = c.dec.Decode(&msg)
+	return
+}
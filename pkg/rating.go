@@ -0,0 +1,167 @@
+package pkg
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"math"
+	"os"
+	"sort"
+)
+
+// InitialRating is the rating of a player's first game.
+const InitialRating = 1500
+
+// Ratings is a ledger of Elo ratings of named players, people or
+// strategies, updated after every hand.
+//
+// A hand counts half as a game between the two teams, each rated by the
+// mean rating of its players, and half as games between every player and
+// the three opponents, won by whoever placed first. A player's rating moves
+// by K times its score, the won share of those games, minus the score its
+// rating difference predicts.
+type Ratings struct {
+	K       float64            `json:"k"`
+	Players map[string]*Rating `json:"players"`
+}
+
+// Rating is the record of a player.
+type Rating struct {
+	Name   string  `json:"-"`
+	Rating float64 `json:"rating"`
+	Hands  int     `json:"hands"`
+	Wins   int     `json:"wins"`
+}
+
+func NewRatings() *Ratings {
+	return &Ratings{
+		K:       32,
+		Players: make(map[string]*Rating),
+	}
+}
+
+// LoadRatings reads a ledger written by Save, a missing file is an empty
+// ledger.
+func LoadRatings(path string) (*Ratings, error) {
+	r := NewRatings()
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return r, nil
+	} else if err != nil {
+		return nil, err
+	}
+	if err = json.Unmarshal(data, r); err != nil {
+		return nil, err
+	}
+	if r.Players == nil {
+		r.Players = make(map[string]*Rating)
+	}
+	return r, nil
+}
+
+func (r *Ratings) Save(path string) error {
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, append(data, '\n'), 0644)
+}
+
+// Get returns the record of name, the initial rating if it has none.
+func (r *Ratings) Get(name string) Rating {
+	if p, ok := r.Players[name]; ok {
+		rating := *p
+		rating.Name = name
+		return rating
+	}
+	return Rating{Name: name, Rating: InitialRating}
+}
+
+// Record updates the ratings of the players of the six seats after a hand
+// played by the teams. Seats without a name are played by an unrated
+// player of the initial rating. A player of several seats plays the hand
+// once, moved by the changes of all its seats, and wins it only if all of
+// them were on the winning team.
+func (r *Ratings) Record(names [6]string, teams [6]uint32, result HandResult) {
+	var before [6]float64
+	for seat, name := range names {
+		before[seat] = r.Get(name).Rating
+	}
+	teamRating := make(map[uint32]float64)
+	members := make(map[uint32]float64)
+	for seat, team := range teams {
+		teamRating[team] += before[seat]
+		members[team]++
+	}
+	place := make(map[int]int)
+	for i, seat := range result.Ranking {
+		place[seat] = i
+	}
+	var delta [6]float64
+	for seat, team := range teams {
+		var score, expected, opponents, otherRating float64
+		for other, otherTeam := range teams {
+			if otherTeam == team {
+				continue
+			}
+			opponents++
+			if place[seat] < place[other] {
+				score++
+			}
+			expected += eloExpected(before[seat], before[other])
+			otherRating += before[other]
+		}
+		if opponents == 0 {
+			continue
+		}
+		score, expected = score/opponents, expected/opponents
+		if result.Winner == team {
+			score++
+		}
+		expected += eloExpected(teamRating[team]/members[team], otherRating/opponents)
+		delta[seat] = r.K * (score - expected) / 2
+	}
+	lost := make(map[string]bool)
+	for seat, name := range names {
+		lost[name] = lost[name] || result.Winner != teams[seat]
+	}
+	counted := make(map[string]bool)
+	for seat, name := range names {
+		if name == "" {
+			continue
+		}
+		p, ok := r.Players[name]
+		if !ok {
+			p = &Rating{Rating: InitialRating}
+			r.Players[name] = p
+		}
+		p.Rating += delta[seat]
+		if counted[name] {
+			continue
+		}
+		counted[name] = true
+		p.Hands++
+		if !lost[name] {
+			p.Wins++
+		}
+	}
+}
+
+// eloExpected returns the score a player rated a is expected to make
+// against a player rated b.
+func eloExpected(a, b float64) float64 {
+	return 1 / (1 + math.Pow(10, (b-a)/400))
+}
+
+// Leaderboard lists the players from the highest rating to the lowest.
+func (r *Ratings) Leaderboard() (board []Rating) {
+	for name := range r.Players {
+		board = append(board, r.Get(name))
+	}
+	sort.Slice(board, func(i, j int) bool {
+		if board[i].Rating != board[j].Rating {
+			return board[i].Rating > board[j].Rating
+		}
+		return board[i].Name < board[j].Name
+	})
+	return
+}
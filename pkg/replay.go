@@ -0,0 +1,182 @@
+package pkg
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// ReplayVersion is the version of the replay files written by
+// ReplayRecorder.
+const ReplayVersion = 1
+
+// A replay file holds JSON values, one per line. Every hand starts with a
+// header line
+//
+//	{"type":"header","version":1,"rules":{...},"seed":5,"hands":["S3 H3 ...",...],"leader":2,"seats":["human","normal",...]}
+//
+// followed by a line for every shot and pass in order
+//
+//	{"type":"move","turn":1,"seat":2,"cards":"S3 H3","shotType":2}
+//	{"type":"move","turn":2,"seat":3,"shotType":0}
+//
+// Cards are written as by Cards.String and the hands are the ones played,
+// after any tribute. A file may hold several hands of a match.
+type ReplayHeader struct {
+	Version int       `json:"version"`
+	Rules   Rules     `json:"rules"`
+	Seed    int64     `json:"seed"`
+	Hands   [6]Cards  `json:"hands"`
+	Leader  int       `json:"leader"`
+	Seats   [6]string `json:"seats"`
+}
+
+type ReplayMove struct {
+	Turn     int      `json:"turn"`
+	Seat     int      `json:"seat"`
+	Cards    Cards    `json:"cards,omitempty"`
+	ShotType ShotType `json:"shotType"`
+}
+
+func (m ReplayMove) Shot() Shot {
+	return Shot{
+		Cards: m.Cards,
+		Type:  m.ShotType,
+	}
+}
+
+type replayLine struct {
+	Type string `json:"type"`
+	*ReplayHeader
+	*ReplayMove
+}
+
+// Replay is a recorded hand.
+type Replay struct {
+	ReplayHeader
+	Moves []ReplayMove
+}
+
+// ReplayRecorder writes the hands of a game to a replay file while they are
+// played.
+type ReplayRecorder struct {
+	g     *Game
+	enc   *json.Encoder
+	seats [6]string
+	err   error
+}
+
+// RecordReplay subscribes a ReplayRecorder to g. seats names the strategies
+// of the seats and may be left empty.
+func RecordReplay(g *Game, w io.Writer, seats [6]string) *ReplayRecorder {
+	r := &ReplayRecorder{
+		g:     g,
+		enc:   json.NewEncoder(w),
+		seats: seats,
+	}
+	g.Subscribe(r)
+	return r
+}
+
+func (r *ReplayRecorder) OnEvent(e Event) {
+	switch e := e.(type) {
+	case HandDealt:
+		r.write(replayLine{
+			Type: "header",
+			ReplayHeader: &ReplayHeader{
+				Version: ReplayVersion,
+				Rules:   r.g.Rules,
+				Seed:    r.g.Seed,
+				Hands:   e.Hands,
+				Leader:  e.Leader,
+				Seats:   r.seats,
+			},
+		})
+	case ShotPlayed:
+		r.write(replayLine{
+			Type: "move",
+			ReplayMove: &ReplayMove{
+				Turn:     e.Turn,
+				Seat:     e.Seat,
+				Cards:    e.Shot.Cards,
+				ShotType: e.Shot.Type,
+			},
+		})
+	case Passed:
+		r.write(replayLine{
+			Type: "move",
+			ReplayMove: &ReplayMove{
+				Turn: e.Turn,
+				Seat: e.Seat,
+			},
+		})
+	}
+}
+
+func (r *ReplayRecorder) write(line replayLine) {
+	if r.err == nil {
+		r.err = r.enc.Encode(line)
+	}
+}
+
+// Err returns the first error met writing the replay.
+func (r *ReplayRecorder) Err() error {
+	return r.err
+}
+
+// ReadReplays reads the hands of a replay file.
+func ReadReplays(r io.Reader) (replays []Replay, err error) {
+	dec := json.NewDecoder(r)
+	for n := 1; ; n++ {
+		var line replayLine
+		if err = dec.Decode(&line); errors.Is(err, io.EOF) {
+			return replays, nil
+		} else if err != nil {
+			return nil, fmt.Errorf("replay line %d: %v", n, err)
+		}
+		switch {
+		case line.Type == "header" && line.ReplayHeader != nil:
+			if line.Version != ReplayVersion {
+				return nil, fmt.Errorf("replay line %d: unsupported version %d", n, line.Version)
+			}
+			replays = append(replays, Replay{
+				ReplayHeader: *line.ReplayHeader,
+			})
+		case line.Type == "move" && line.ReplayMove != nil:
+			if len(replays) == 0 {
+				return nil, fmt.Errorf("replay line %d: move before header", n)
+			}
+			replay := &replays[len(replays)-1]
+			replay.Moves = append(replay.Moves, *line.ReplayMove)
+		default:
+			return nil, fmt.Errorf("replay line %d: unknown type %q", n, line.Type)
+		}
+	}
+}
+
+// Game sets the recorded hand up and applies its first turns moves, all of
+// them if turns is negative. Every move is checked by Game.Apply.
+func (r *Replay) Game(turns int) (g Game, err error) {
+	if turns < 0 || turns > len(r.Moves) {
+		turns = len(r.Moves)
+	}
+	if err = r.Rules.Validate(); err != nil {
+		return
+	}
+	g = NewGame(GameOptions{
+		Seed:  r.Seed,
+		Rules: &r.Rules,
+	})
+	g.DealHands(r.Hands)
+	g.SetLeader(r.Leader)
+	for _, move := range r.Moves[:turns] {
+		if move.Turn != g.Turns()+1 {
+			return g, fmt.Errorf("turn %d: expected turn %d", move.Turn, g.Turns()+1)
+		}
+		if err = g.Apply(move.Seat, move.Shot()); err != nil {
+			return g, fmt.Errorf("turn %d: %v", move.Turn, err)
+		}
+	}
+	return
+}
@@ -0,0 +1,493 @@
+package pkg
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+var defaultRules = DefaultRules()
+
+// Rules holds the house rules of a game.
+type Rules struct {
+	// Decks is the number of 52-card decks shuffled together.
+	Decks int `json:"decks" yaml:"decks"`
+	// Jokers adds the two jokers of every deck.
+	Jokers bool `json:"jokers" yaml:"jokers"`
+	// ShotTypes lists the shot types that may be played.
+	ShotTypes []ShotType `json:"shotTypes" yaml:"shotTypes"`
+	// FiveBeatsOnlyFive keeps five-card shots to rounds of five-card shots.
+	// Without it a five-card shot may also be played on any single, pair or
+	// triple.
+	FiveBeatsOnlyFive bool `json:"fiveBeatsOnlyFive" yaml:"fiveBeatsOnlyFive"`
+	// FiveOfAKindBeatsAll lets five of a kind be played on any shot.
+	FiveOfAKindBeatsAll bool `json:"fiveOfAKindBeatsAll" yaml:"fiveOfAKindBeatsAll"`
+	// TwoHigh ranks the 2 above the ace, otherwise it ranks below the 3 and
+	// straights run from 2-3-4-5-6 up to 0-J-Q-K-A.
+	TwoHigh bool `json:"twoHigh" yaml:"twoHigh"`
+	// JokersInFive allows jokers in five-card shots.
+	JokersInFive bool `json:"jokersInFive" yaml:"jokersInFive"`
+	// FriendPassMax is the highest number the built-in AI still beats when
+	// its teammate played it; higher shots of a teammate are let through.
+	FriendPassMax uint32 `json:"friendPassMax" yaml:"friendPassMax"`
+	// Teams gives the team of every seat, two teams of three seats.
+	Teams [6]uint32 `json:"teams" yaml:"teams"`
+	// Bombs decides which shots are bombs.
+	Bombs BombRules `json:"bombs" yaml:"bombs"`
+}
+
+// BombRules lists the bombs of a game. A bomb may be played as a shot of
+// type ShotTypeBomb on any shot whose cards are not a bomb, and only a
+// larger bomb beats it, even when its cards were played as another type. More cards make a larger bomb; among bombs of the same size
+// jokers beat cards of a kind, which beat straight flushes as they do in
+// FiveCardCategory, and then the larger rank wins: the number for cards of
+// a kind, the highest card for a straight flush and the number of big
+// jokers for jokers.
+type BombRules struct {
+	// MinOfAKind is the least number of equal cards that make a bomb, zero
+	// for no such bombs.
+	MinOfAKind int `json:"minOfAKind" yaml:"minOfAKind"`
+	// StraightFlush makes every straight flush a bomb.
+	StraightFlush bool `json:"straightFlush" yaml:"straightFlush"`
+	// MinJokers is the least number of jokers, small or big, that make a
+	// bomb, zero for no such bombs.
+	MinJokers int `json:"minJokers" yaml:"minJokers"`
+}
+
+type BombKind uint32
+
+const (
+	BombStraightFlush BombKind = iota
+	BombOfAKind
+	BombJokers
+)
+
+// BombRank orders bombs, see BombRules.
+type BombRank struct {
+	Size int
+	Kind BombKind
+	Rank uint32
+}
+
+// Compare returns 1 if r beats o, -1 if o beats r and 0 if neither does.
+func (r BombRank) Compare(o BombRank) int {
+	switch {
+	case r.Size != o.Size:
+		return cmpUint32(uint32(r.Size), uint32(o.Size))
+	case r.Kind != o.Kind:
+		return cmpUint32(uint32(r.Kind), uint32(o.Kind))
+	default:
+		return cmpUint32(r.Rank, o.Rank)
+	}
+}
+
+func cmpUint32(a, b uint32) int {
+	if a > b {
+		return 1
+	} else if a < b {
+		return -1
+	}
+	return 0
+}
+
+func DefaultRules() Rules {
+	return Rules{
+		Decks:             3,
+		Jokers:            true,
+		ShotTypes:         []ShotType{ShotTypeOne, ShotTypeTwo, ShotTypeThree, ShotTypeFive},
+		FiveBeatsOnlyFive: true,
+		TwoHigh:           true,
+		JokersInFive:      true,
+		FriendPassMax:     9,
+		Teams:             [6]uint32{1, 2, 1, 2, 1, 2},
+		Bombs: BombRules{
+			MinOfAKind:    4,
+			StraightFlush: true,
+			MinJokers:     4,
+		},
+	}
+}
+
+// LoadRules reads rules from a JSON or, for .yaml and .yml files, a YAML
+// file. Settings missing from the file keep their DefaultRules value, unknown
+// settings are an error.
+func LoadRules(path string) (Rules, error) {
+	rules := DefaultRules()
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return rules, err
+	}
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		err = yaml.UnmarshalStrict(data, &rules)
+	default:
+		dec := json.NewDecoder(bytes.NewReader(data))
+		dec.DisallowUnknownFields()
+		err = dec.Decode(&rules)
+	}
+	if err != nil {
+		return rules, fmt.Errorf("bad rules %s: %v", path, err)
+	}
+	if err = rules.Validate(); err != nil {
+		return rules, fmt.Errorf("bad rules %s: %v", path, err)
+	}
+	return rules, nil
+}
+
+func (r *Rules) Validate() error {
+	if r.Decks < 1 {
+		return fmt.Errorf("need at least one deck, got %d", r.Decks)
+	}
+	singles := false
+	for _, t := range r.ShotTypes {
+		switch t {
+		case ShotTypeOne:
+			singles = true
+		case ShotTypeTwo, ShotTypeThree, ShotTypeFive:
+		default:
+			return fmt.Errorf("unknown shot type %d", t)
+		}
+	}
+	// every hand must be able to lead
+	if !singles {
+		return fmt.Errorf("single shots must be allowed")
+	}
+	if b := r.Bombs; b.MinOfAKind < 0 || 0 < b.MinOfAKind && b.MinOfAKind < 4 {
+		return fmt.Errorf("bombs need at least four cards of a kind, got %d", b.MinOfAKind)
+	}
+	if b := r.Bombs; b.MinJokers < 0 || b.MinJokers == 1 {
+		return fmt.Errorf("bombs need at least two jokers, got %d", b.MinJokers)
+	}
+	seats := make(map[uint32]int)
+	for _, team := range r.Teams {
+		if team == 0 {
+			return fmt.Errorf("teams must be numbered from 1, got %v", r.Teams)
+		}
+		seats[team]++
+	}
+	if len(seats) != 2 {
+		return fmt.Errorf("need two teams of three seats, got %v", r.Teams)
+	}
+	for _, n := range seats {
+		if n != 3 {
+			return fmt.Errorf("need two teams of three seats, got %v", r.Teams)
+		}
+	}
+	return nil
+}
+
+// TeamNumbers returns the numbers of the two teams, the team of seat 0
+// first.
+func (r *Rules) TeamNumbers() []uint32 {
+	teams := []uint32{r.Teams[0]}
+	for _, team := range r.Teams {
+		if team != teams[0] {
+			return append(teams, team)
+		}
+	}
+	return teams
+}
+
+// Deck returns all cards of the game, unshuffled.
+func (r *Rules) Deck() (cards Cards) {
+	for num := 0; num < r.Decks; num++ {
+		for i := 3; i <= 15; i++ {
+			cards = append(cards, initialCard(i)...)
+		}
+		if r.Jokers {
+			cards = append(cards, Card{Num: 21})
+			cards = append(cards, Card{Num: 22})
+		}
+	}
+	return
+}
+
+// Rank maps a card number to its strength: the number itself, except for
+// the 2 which ranks as 2 unless TwoHigh.
+func (r *Rules) Rank(num uint32) uint32 {
+	if num == 15 && !r.TwoHigh {
+		return 2
+	}
+	return num
+}
+
+// RankFive categorizes five cards, see FiveCardCategory. The keys of the
+// returned rank hold card ranks, see Rules.Rank.
+func (r *Rules) RankFive(c Cards) (FiveCardRank, error) {
+	if len(c) != 5 {
+		return FiveCardRank{}, fmt.Errorf("bad 5-cards %q", c)
+	}
+	cards := c.Copy()
+	sort.Slice(cards, func(i, j int) bool { return r.Rank(cards[i].Num) < r.Rank(cards[j].Num) })
+
+	// ranks by count, the larger count first, then the larger rank
+	counts := make(map[uint32]int)
+	for _, card := range cards {
+		if card.IsJoker() && !r.JokersInFive {
+			return FiveCardRank{}, fmt.Errorf("bad 5-cards %q: jokers are not allowed", c)
+		}
+		counts[r.Rank(card.Num)]++
+	}
+	var ranks []uint32
+	for rank := range counts {
+		ranks = append(ranks, rank)
+	}
+	sort.Slice(ranks, func(i, j int) bool {
+		if counts[ranks[i]] != counts[ranks[j]] {
+			return counts[ranks[i]] > counts[ranks[j]]
+		}
+		return ranks[i] > ranks[j]
+	})
+
+	flush, straight := true, true
+	for i, card := range cards {
+		if card.IsJoker() || card.Color != cards[0].Color {
+			flush = false
+		}
+		if card.IsJoker() || i > 0 && r.Rank(cards[i-1].Num)+1 != r.Rank(card.Num) {
+			straight = false
+		}
+	}
+	high := r.Rank(cards[4].Num)
+	switch {
+	case len(ranks) == 1:
+		return FiveCardRank{FiveCardFiveOfAKind, ranks}, nil
+	case straight && flush:
+		return FiveCardRank{FiveCardStraightFlush, []uint32{high}}, nil
+	case counts[ranks[0]] == 4:
+		return FiveCardRank{FiveCardFourOfAKind, ranks}, nil
+	case counts[ranks[0]] == 3 && counts[ranks[1]] == 2:
+		return FiveCardRank{FiveCardFullHouse, ranks}, nil
+	case flush:
+		keys := make([]uint32, 5)
+		for i, card := range cards {
+			keys[4-i] = r.Rank(card.Num)
+		}
+		return FiveCardRank{FiveCardFlush, keys}, nil
+	case straight:
+		return FiveCardRank{FiveCardStraight, []uint32{high}}, nil
+	}
+	return FiveCardRank{}, fmt.Errorf("bad 5-cards %q", c)
+}
+
+// RankBomb fails unless cards are a bomb, see BombRules.
+func (r *Rules) RankBomb(cards Cards) (BombRank, error) {
+	if len(cards) == 0 {
+		return BombRank{}, fmt.Errorf("no cards")
+	}
+	jokers, big, same := 0, 0, true
+	for _, card := range cards {
+		if card.IsJoker() {
+			jokers++
+		}
+		if card.Num == 22 {
+			big++
+		}
+		if card.Num != cards[0].Num {
+			same = false
+		}
+	}
+	b := r.Bombs
+	if b.MinJokers > 0 && jokers == len(cards) && jokers >= b.MinJokers {
+		return BombRank{len(cards), BombJokers, uint32(big)}, nil
+	}
+	if b.MinOfAKind > 0 && same && len(cards) >= b.MinOfAKind {
+		return BombRank{len(cards), BombOfAKind, r.Rank(cards[0].Num)}, nil
+	}
+	if b.StraightFlush && len(cards) == 5 {
+		rank, err := r.RankFive(cards)
+		if err == nil && rank.Category == FiveCardStraightFlush {
+			return BombRank{5, BombStraightFlush, rank.Keys[0]}, nil
+		}
+	}
+	return BombRank{}, fmt.Errorf("%q is not a bomb", cards)
+}
+
+// ShotTypeOf returns the type of the shot made of cards, failing unless the
+// cards form a shot of an allowed type. Cards that are both a bomb and a
+// shot of another type get the other type, see ShotFor.
+func (r *Rules) ShotTypeOf(cards Cards) (ShotType, error) {
+	t, err := r.plainShotType(cards)
+	if err != nil {
+		if _, bombErr := r.RankBomb(cards); bombErr == nil {
+			return ShotTypeBomb, nil
+		}
+	}
+	return t, err
+}
+
+func (r *Rules) plainShotType(cards Cards) (ShotType, error) {
+	var t ShotType
+	switch len(cards) {
+	case 1, 2, 3:
+		for _, card := range cards {
+			if card.Num != cards[0].Num {
+				return ShotTypePass, fmt.Errorf("bad cards %q", cards)
+			}
+		}
+		t = ShotType(len(cards))
+	case 5:
+		if _, err := r.RankFive(cards); err != nil {
+			return ShotTypePass, err
+		}
+		t = ShotTypeFive
+	default:
+		return ShotTypePass, fmt.Errorf("bad cards: %q", cards)
+	}
+	for _, allowed := range r.ShotTypes {
+		if allowed == t {
+			return t, nil
+		}
+	}
+	return ShotTypePass, fmt.Errorf("%d-card shots are not allowed", t)
+}
+
+// ShotFor makes a shot of cards that may be played on cur, playing them as
+// a bomb when they only beat cur that way.
+func (r *Rules) ShotFor(cards Cards, cur Shot) (Shot, error) {
+	t, err := r.ShotTypeOf(cards)
+	if err != nil {
+		return Shot{}, err
+	}
+	shot := Shot{
+		Cards: cards,
+		Type:  t,
+	}
+	err = r.Beats(shot, cur)
+	if err != nil && t != ShotTypeBomb {
+		bomb := Shot{
+			Cards: cards,
+			Type:  ShotTypeBomb,
+		}
+		if r.Beats(bomb, cur) == nil {
+			return bomb, nil
+		}
+	}
+	return shot, err
+}
+
+// Compare returns 1 if a beats b, -1 if b beats a and 0 if neither does.
+// Both must be valid shots of the same type or both bombs.
+func (r *Rules) Compare(a, b Cards) (int, error) {
+	ta, err := r.ShotTypeOf(a)
+	if err != nil {
+		return 0, err
+	}
+	tb, err := r.ShotTypeOf(b)
+	if err != nil {
+		return 0, err
+	}
+	if ta != tb {
+		ra, errA := r.RankBomb(a)
+		rb, errB := r.RankBomb(b)
+		if errA == nil && errB == nil {
+			return ra.Compare(rb), nil
+		}
+		return 0, fmt.Errorf("cards length not equal: %q, %q", a, b)
+	}
+	switch ta {
+	case ShotTypeFive:
+		ra, _ := r.RankFive(a)
+		rb, _ := r.RankFive(b)
+		return ra.Compare(rb), nil
+	case ShotTypeBomb:
+		ra, _ := r.RankBomb(a)
+		rb, _ := r.RankBomb(b)
+		return ra.Compare(rb), nil
+	}
+	return cmpUint32(r.Rank(a[0].Num), r.Rank(b[0].Num)), nil
+}
+
+// Beats returns an error unless shot is a valid shot that may be played on
+// cur. It does not check who holds the cards.
+func (r *Rules) Beats(shot Shot, cur Shot) error {
+	if shot.Type == ShotTypeBomb {
+		rank, err := r.RankBomb(shot.Cards)
+		if err != nil {
+			return err
+		}
+		if cur.Type == ShotTypePass {
+			return nil
+		}
+		// cards that are a bomb keep their rank when played as another
+		// type, like a straight flush played as a five-card shot
+		curRank, err := r.RankBomb(cur.Cards)
+		if err != nil {
+			if cur.Type == ShotTypeBomb {
+				return err
+			}
+			return nil
+		}
+		if rank.Compare(curRank) <= 0 {
+			return fmt.Errorf("%q is not larger than %q", shot.Cards, cur.Cards)
+		}
+		return nil
+	}
+	t, err := r.plainShotType(shot.Cards)
+	if err != nil {
+		return err
+	}
+	if shot.Type != t {
+		return fmt.Errorf("bad shot type %d for %q", shot.Type, shot.Cards)
+	}
+	if cur.Type == ShotTypePass {
+		return nil
+	}
+	if cur.Type == ShotTypeBomb {
+		return fmt.Errorf("only a larger bomb beats %q", cur.Cards)
+	}
+	if shot.Type != cur.Type {
+		if r.beatsAnyType(shot) {
+			return nil
+		}
+		return fmt.Errorf("expect %d cards, got %q", cur.Type, shot.Cards)
+	}
+	cmp, err := r.Compare(shot.Cards, cur.Cards)
+	if err != nil {
+		return err
+	}
+	if cmp <= 0 {
+		return fmt.Errorf("%q is not larger than %q", shot.Cards, cur.Cards)
+	}
+	return nil
+}
+
+// fiveBeatsAnyType reports whether some five-card shots may be played on
+// shots of other types.
+func (r *Rules) fiveBeatsAnyType() bool {
+	return !r.FiveBeatsOnlyFive || r.FiveOfAKindBeatsAll
+}
+
+func (r *Rules) beatsAnyType(shot Shot) bool {
+	if shot.Type != ShotTypeFive {
+		return false
+	}
+	if !r.FiveBeatsOnlyFive {
+		return true
+	}
+	rank, err := r.RankFive(shot.Cards)
+	return err == nil && r.FiveOfAKindBeatsAll && rank.Category == FiveCardFiveOfAKind
+}
+
+// CheckShot returns an error unless hand holds shot's cards and shot may be
+// played on cur.
+func (r *Rules) CheckShot(hand Cards, cur Shot, shot Shot) error {
+	if len(shot.Cards) == 0 {
+		return fmt.Errorf("no cards")
+	}
+	curCards := hand.Copy()
+	for _, card := range shot.Cards {
+		idx := curCards.index(card, true)
+		if idx < 0 {
+			return fmt.Errorf("cards %q not in hand", shot.Cards)
+		}
+		curCards = append(curCards[:idx], curCards[idx+1:]...)
+	}
+	return r.Beats(shot, cur)
+}
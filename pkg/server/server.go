@@ -0,0 +1,419 @@
+// Package server hosts a table of six seats over the network. Clients speak
+// the line-based JSON protocol of pkg.Message: they join a seat, see only
+// their own hand besides the public state of the table, and their shots are
+// checked by the server before they are played. Seats nobody joined are
+// played by an AI strategy.
+package server
+
+import (
+	"fmt"
+	"net"
+	"sync"
+	"time"
+
+	"CardGame3V3Go/pkg"
+)
+
+// Server runs one match at a table. The match starts when all seats are
+// taken or a seated client sends a start message.
+type Server struct {
+	Options pkg.GameOptions
+	// Target is the score that ends the match, 0 plays a single hand.
+	Target int
+	// AI is the strategy of the seats without a client.
+	AI string
+	// Ratings, if set, is updated after every hand with the names of the
+	// clients and of the AI.
+	Ratings *pkg.Ratings
+	// TurnTime is how long a client may take for its shot or to read a
+	// message. A client that takes longer loses its seat to the AI. If zero
+	// shots take any time, but reading a message still takes at most
+	// MaxWriteTime.
+	TurnTime time.Duration
+
+	mu      sync.Mutex
+	clients [6]*client
+	started bool
+	start   chan struct{}
+}
+
+type client struct {
+	conn *pkg.MessageConn
+	raw  net.Conn
+	name string
+	seat int
+	// waiting is set while the seat's strategy waits for a move on moves,
+	// which is closed when the client left.
+	waiting bool
+	left    bool
+	moves   chan pkg.Message
+}
+
+func New(opts pkg.GameOptions, target int) *Server {
+	return &Server{
+		Options:  opts,
+		Target:   target,
+		AI:       "normal",
+		TurnTime: 2 * time.Minute,
+		start:    make(chan struct{}),
+	}
+}
+
+func (s *Server) ListenAndServe(addr string) error {
+	l, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+	defer l.Close()
+	return s.Serve(l)
+}
+
+// Serve accepts clients on l and plays the match. It returns when the match
+// is over, l is left open.
+func (s *Server) Serve(l net.Listener) error {
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			go s.ServeConn(conn)
+		}
+	}()
+	return s.Run()
+}
+
+// ServeConn talks to a client until it leaves.
+func (s *Server) ServeConn(conn net.Conn) {
+	defer conn.Close()
+	mc := pkg.NewMessageConn(timedConn{conn, s.TurnTime})
+	var c *client
+	defer func() {
+		s.leave(c)
+	}()
+	for {
+		msg, err := mc.Receive()
+		if err != nil {
+			return
+		}
+		switch msg.Type {
+		case pkg.MsgJoin:
+			var join pkg.Join
+			if err = msg.Decode(&join); err == nil && c != nil {
+				err = fmt.Errorf("already seated at %d", c.seat)
+			}
+			if err == nil {
+				c, err = s.join(conn, mc, join)
+			}
+		case pkg.MsgStart:
+			if c == nil {
+				err = fmt.Errorf("join a seat first")
+			} else {
+				s.begin()
+			}
+		case pkg.MsgPlay, pkg.MsgPass:
+			if c == nil || !s.move(c, msg) {
+				err = fmt.Errorf("not your turn")
+			}
+		default:
+			err = fmt.Errorf("unknown message type %q", msg.Type)
+		}
+		if err != nil {
+			mc.Send(pkg.MsgError, pkg.ErrorMsg{Error: err.Error()})
+		}
+	}
+}
+
+func (s *Server) join(conn net.Conn, mc *pkg.MessageConn, join pkg.Join) (*client, error) {
+	c, full, err := s.seat(conn, mc, join)
+	if err != nil {
+		return nil, err
+	}
+	if full {
+		s.begin()
+	}
+	return c, nil
+}
+
+// seat seats a joining client and reports whether the table is full. The
+// client hears of its seat before the lock is released, so before the match
+// can start and deal.
+func (s *Server) seat(conn net.Conn, mc *pkg.MessageConn, join pkg.Join) (c *client, full bool, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.started {
+		return nil, false, fmt.Errorf("game already started")
+	}
+	teams := s.teams()
+	seat := -1
+	for i := range s.clients {
+		free := s.clients[i] == nil
+		if join.Seat != nil && *join.Seat == i && !free {
+			return nil, false, fmt.Errorf("seat %d is taken", i)
+		}
+		if free && (join.Seat == nil || *join.Seat == i) && (join.Team == 0 || join.Team == teams[i]) {
+			seat = i
+			break
+		}
+	}
+	if seat < 0 {
+		return nil, false, fmt.Errorf("no free seat for %+v", join)
+	}
+	c = &client{
+		conn:  mc,
+		raw:   conn,
+		name:  join.Name,
+		seat:  seat,
+		moves: make(chan pkg.Message, 1),
+	}
+	s.clients[seat] = c
+	mc.Send(pkg.MsgSeated, pkg.Seated{Seat: seat, Team: teams[seat]})
+	full = true
+	for _, c := range s.clients {
+		full = full && c != nil
+	}
+	return c, full, nil
+}
+
+func (s *Server) teams() [6]uint32 {
+	if s.Options.Rules != nil {
+		return s.Options.Rules.Teams
+	}
+	return pkg.DefaultRules().Teams
+}
+
+func (s *Server) leave(c *client) {
+	if c == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.started {
+		s.clients[c.seat] = nil
+		return
+	}
+	s.leaveLocked(c)
+}
+
+func (s *Server) leaveLocked(c *client) {
+	if c.left {
+		return
+	}
+	c.waiting = false
+	c.left = true
+	close(c.moves)
+}
+
+// drop hands the seat of c to the AI and closes the client's connection.
+func (s *Server) drop(c *client, reason string) {
+	s.mu.Lock()
+	s.leaveLocked(c)
+	s.mu.Unlock()
+	c.conn.Send(pkg.MsgError, pkg.ErrorMsg{Error: reason})
+	c.raw.Close()
+}
+
+func (s *Server) begin() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.beginLocked()
+}
+
+func (s *Server) beginLocked() {
+	if !s.started {
+		s.started = true
+		close(s.start)
+	}
+}
+
+// move hands msg to the seat's strategy if it waits for one.
+func (s *Server) move(c *client, msg pkg.Message) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !c.waiting {
+		return false
+	}
+	c.waiting = false
+	c.moves <- msg
+	return true
+}
+
+// Run waits for the match to start and plays it.
+func (s *Server) Run() error {
+	<-s.start
+	m := pkg.NewMatch(s.Options, s.Target)
+	g := &m.Game
+	var names [6]string
+	for i := range g.Players {
+		names[i] = s.AI
+		if c := s.clients[i]; c != nil {
+			names[i] = c.name
+		}
+		ai, err := pkg.NewStrategy(s.AI)
+		if err != nil {
+			return err
+		}
+		g.Players[i].Strategy = ai
+		if c := s.clients[i]; c != nil {
+			g.Players[i].Strategy = &remoteStrategy{
+				s:  s,
+				c:  c,
+				ai: ai,
+			}
+		}
+	}
+	g.Subscribe(pkg.ObserverFunc(func(e pkg.Event) {
+		s.broadcast(g, names, e)
+	}))
+	for {
+		m.StartHand()
+		if _, err := g.Play(); err != nil {
+			return err
+		}
+		hand, err := m.FinishHand()
+		if err != nil {
+			return err
+		}
+		if s.Ratings != nil {
+			s.Ratings.Record(names, g.Rules.Teams, hand.HandResult)
+		}
+		over := s.Target == 0 || m.Winner() != 0
+		s.sendAll(pkg.MsgHandOver, pkg.HandOverMsg{
+			Result: hand.HandResult,
+			Points: hand.Points,
+			Scores: m.Scores,
+			Over:   over,
+		})
+		if over {
+			return nil
+		}
+	}
+}
+
+func (s *Server) broadcast(g *pkg.Game, names [6]string, e pkg.Event) {
+	switch e := e.(type) {
+	case pkg.HandDealt:
+		for seat, c := range s.clients {
+			if c != nil {
+				c.conn.Send(pkg.MsgDeal, pkg.Deal{
+					Rules:  g.Rules,
+					Seat:   seat,
+					Hand:   e.Hands[seat],
+					Leader: e.Leader,
+					Names:  names,
+					Teams:  g.Rules.Teams,
+				})
+			}
+		}
+	case pkg.ShotPlayed:
+		s.sendAll(pkg.MsgPlayed, pkg.Played{Seat: e.Seat, Turn: e.Turn, Shot: e.Shot})
+	case pkg.Passed:
+		s.sendAll(pkg.MsgPassed, pkg.PassedMsg{Seat: e.Seat, Turn: e.Turn})
+	case pkg.RoundReset:
+		s.sendAll(pkg.MsgRoundReset, pkg.RoundResetMsg{Leader: e.Leader})
+	case pkg.PlayerFinished:
+		s.sendAll(pkg.MsgFinished, pkg.FinishedMsg{Finish: e.Finish, Place: e.Place})
+	}
+}
+
+func (s *Server) sendAll(typ string, data interface{}) {
+	for _, c := range s.clients {
+		if c != nil {
+			c.conn.Send(typ, data)
+		}
+	}
+}
+
+// remoteStrategy asks a client for its shots and checks them before they
+// are played. The seat is played by ai once the client left.
+type remoteStrategy struct {
+	s  *Server
+	c  *client
+	ai pkg.Strategy
+}
+
+func (r *remoteStrategy) NextShot(view pkg.TableView, curShot pkg.Shot) pkg.Shot {
+	if !r.await() {
+		return r.ai.NextShot(view, curShot)
+	}
+	r.c.conn.Send(pkg.MsgTurn, pkg.Turn{
+		Hand:       view.Hand,
+		Shot:       curShot,
+		Friend:     curShot.Type != pkg.ShotTypePass && curShot.Team == view.Team,
+		CardCounts: view.CardCounts,
+	})
+	var timeout <-chan time.Time
+	if r.s.TurnTime > 0 {
+		timer := time.NewTimer(r.s.TurnTime)
+		defer timer.Stop()
+		timeout = timer.C
+	}
+	for {
+		var msg pkg.Message
+		var ok bool
+		select {
+		case msg, ok = <-r.c.moves:
+		case <-timeout:
+			r.s.drop(r.c, "time is up, the AI plays your seat")
+		}
+		if !ok {
+			return r.ai.NextShot(view, curShot)
+		}
+		shot, err := r.shot(view, curShot, msg)
+		if err == nil {
+			return shot
+		}
+		// the turn goes on, so wait before the client hears of the error
+		if !r.await() {
+			return r.ai.NextShot(view, curShot)
+		}
+		r.c.conn.Send(pkg.MsgError, pkg.ErrorMsg{Error: err.Error()})
+	}
+}
+
+// await lets the client's next move through, unless the client left.
+func (r *remoteStrategy) await() bool {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	r.c.waiting = !r.c.left
+	return r.c.waiting
+}
+
+func (r *remoteStrategy) shot(view pkg.TableView, curShot pkg.Shot, msg pkg.Message) (pkg.Shot, error) {
+	if msg.Type == pkg.MsgPass {
+		if curShot.Type == pkg.ShotTypePass {
+			return pkg.Shot{}, fmt.Errorf("you lead this round and cannot pass")
+		}
+		return pkg.Shot{Team: view.Team}, nil
+	}
+	var play pkg.Play
+	if err := msg.Decode(&play); err != nil {
+		return pkg.Shot{}, err
+	}
+	return view.ShotFor(play.Cards, curShot)
+}
+
+// MaxWriteTime is how long a client may take to read a message when the
+// server has no TurnTime.
+const MaxWriteTime = time.Minute
+
+// timedConn gives up a write after timeout, or MaxWriteTime without one,
+// and closes the connection, so a client that stopped reading cannot hold
+// up the table.
+type timedConn struct {
+	net.Conn
+	timeout time.Duration
+}
+
+func (c timedConn) Write(p []byte) (int, error) {
+	timeout := c.timeout
+	if timeout <= 0 {
+		timeout = MaxWriteTime
+	}
+	c.Conn.SetWriteDeadline(time.Now().Add(timeout))
+	n, err := c.Conn.Write(p)
+	if err != nil {
+		c.Conn.Close()
+	}
+	return n, err
+}
@@ -6,31 +6,32 @@ const (
 	ShotTypeTwo   ShotType = 2
 	ShotTypeThree ShotType = 3
 	ShotTypeFive  ShotType = 5
+	// ShotTypeBomb is the type of bombs whatever their size, see BombRules.
+	ShotTypeBomb ShotType = 9
 )
 
 type ShotType uint32
 
 type Shot struct {
-	Cards Cards
-	Type  ShotType
-	Team  uint32
+	Cards Cards    `json:"cards,omitempty"`
+	Type  ShotType `json:"type"`
+	Team  uint32   `json:"team,omitempty"`
 }
 
 func (s Shot) String() string {
 	switch s.Type {
 	case ShotTypePass:
 		return "pass"
-	case ShotTypeOne, ShotTypeTwo, ShotTypeThree, ShotTypeFive:
+	case ShotTypeOne, ShotTypeTwo, ShotTypeThree, ShotTypeFive, ShotTypeBomb:
 		return s.Cards.String()
 	default:
 		return "shot type not yet implemented"
 	}
 }
 
+// CheckLarger reports whether nextCards may be played on the shot under the
+// default rules, as a bomb if need be, see Rules.ShotFor.
 func (s *Shot) CheckLarger(nextCards Cards) bool {
-	isLarger, err := nextCards.Larger(&s.Cards)
-	if err != nil {
-		panic(err)
-	}
-	return isLarger
+	_, err := defaultRules.ShotFor(nextCards, *s)
+	return err == nil
 }
@@ -0,0 +1,169 @@
+package pkg
+
+import (
+	"fmt"
+	"math"
+	"runtime"
+	"sync"
+	"time"
+)
+
+// SimOptions sets up a simulation of single hands between strategies.
+type SimOptions struct {
+	Games int
+	// Seed deals the first game, game i is dealt with Seed+i. A zero Seed
+	// is replaced by a time-based one.
+	Seed int64
+	// Seats are the names of the strategies of the six seats, see
+	// NewStrategy. Every game gets new strategies.
+	Seats [6]string
+	Rules *Rules
+	// Workers is the number of games played at once, runtime.NumCPU() if
+	// zero.
+	Workers int
+}
+
+// SimResult sums up a simulation. Places are counted from 1.
+type SimResult struct {
+	Games   int             `json:"games"`
+	Seed    int64           `json:"seed"`
+	Seats   [6]string       `json:"seats"`
+	Teams   [6]uint32       `json:"teams"`
+	WinRate map[uint32]Stat `json:"winRate"`
+	Place   [6]Stat         `json:"place"`
+	Turns   Stat            `json:"turns"`
+	// Hands are the results of the games in order.
+	Hands []HandResult `json:"-"`
+}
+
+// Stat is the mean of a sample with its standard error.
+type Stat struct {
+	Mean   float64 `json:"mean"`
+	StdErr float64 `json:"stdErr"`
+}
+
+// CI95 returns the 95% confidence interval of the mean by the normal
+// approximation.
+func (s Stat) CI95() (lo, hi float64) {
+	return s.Mean - 1.96*s.StdErr, s.Mean + 1.96*s.StdErr
+}
+
+func (s Stat) String() string {
+	return fmt.Sprintf("%.3f ± %.3f", s.Mean, 1.96*s.StdErr)
+}
+
+func newStat(xs []float64) (s Stat) {
+	if len(xs) == 0 {
+		return
+	}
+	for _, x := range xs {
+		s.Mean += x
+	}
+	s.Mean /= float64(len(xs))
+	if len(xs) < 2 {
+		return
+	}
+	var ss float64
+	for _, x := range xs {
+		ss += (x - s.Mean) * (x - s.Mean)
+	}
+	s.StdErr = math.Sqrt(ss / float64(len(xs)-1) / float64(len(xs)))
+	return
+}
+
+// Simulate plays opts.Games seeded hands without anyone watching and sums
+// up their results. The games are spread over opts.Workers goroutines. The
+// result does not depend on their number, unless a strategy limits its
+// search by time, as mcts and hard do with MCTSStrategy.Budget.
+func Simulate(opts SimOptions) (result SimResult, err error) {
+	if opts.Games < 0 {
+		return result, fmt.Errorf("negative number of games %d", opts.Games)
+	}
+	if opts.Rules != nil {
+		if err = opts.Rules.Validate(); err != nil {
+			return
+		}
+	}
+	for _, name := range opts.Seats {
+		if _, err = NewStrategy(name); err != nil {
+			return
+		}
+	}
+	if opts.Seed == 0 {
+		opts.Seed = time.Now().UnixNano()
+	}
+	workers := opts.Workers
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+	results := make([]HandResult, opts.Games)
+	errs := make([]error, opts.Games)
+	games := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range games {
+				results[i], errs[i] = simulateGame(opts, opts.Seed+int64(i))
+			}
+		}()
+	}
+	for i := 0; i < opts.Games; i++ {
+		games <- i
+	}
+	close(games)
+	wg.Wait()
+	for i, err := range errs {
+		if err != nil {
+			return result, fmt.Errorf("game %d: %v", i, err)
+		}
+	}
+
+	rules := DefaultRules()
+	if opts.Rules != nil {
+		rules = *opts.Rules
+	}
+	result = SimResult{
+		Games:   opts.Games,
+		Seed:    opts.Seed,
+		Seats:   opts.Seats,
+		Teams:   rules.Teams,
+		WinRate: make(map[uint32]Stat),
+		Hands:   results,
+	}
+	wins := make(map[uint32][]float64)
+	for _, team := range rules.Teams {
+		wins[team] = make([]float64, len(results))
+	}
+	var places [6][]float64
+	turns := make([]float64, len(results))
+	for i, r := range results {
+		wins[r.Winner][i] = 1
+		for place, seat := range r.Ranking {
+			places[seat] = append(places[seat], float64(place+1))
+		}
+		turns[i] = float64(r.Turns)
+	}
+	for team, xs := range wins {
+		result.WinRate[team] = newStat(xs)
+	}
+	for seat := range places {
+		result.Place[seat] = newStat(places[seat])
+	}
+	result.Turns = newStat(turns)
+	return
+}
+
+func simulateGame(opts SimOptions, seed int64) (HandResult, error) {
+	g := NewGame(GameOptions{Seed: seed, Rules: opts.Rules})
+	for i, name := range opts.Seats {
+		s, err := NewStrategy(name)
+		if err != nil {
+			return HandResult{}, err
+		}
+		g.Players[i].Strategy = s
+	}
+	g.Start()
+	return g.Play()
+}
@@ -0,0 +1,79 @@
+package pkg
+
+import (
+	"fmt"
+	"sort"
+)
+
+var strategies = make(map[string]func() Strategy)
+
+// Strategy decides the shots of one seat. NextShot gets a read-only view of
+// the table and the shot to beat, which is a pass when the seat leads a new
+// round, and returns the cards to play or a pass.
+type Strategy interface {
+	NextShot(view TableView, curShot Shot) Shot
+}
+
+type StrategyFunc func(view TableView, curShot Shot) Shot
+
+func (f StrategyFunc) NextShot(view TableView, curShot Shot) Shot {
+	return f(view, curShot)
+}
+
+// TableView is what a seat can see of the table. Hand is a copy of the
+// seat's own cards; of the other seats only the card counts and the cards
+// they played in this hand are known.
+type TableView struct {
+	Rules      Rules
+	Seat       int
+	Team       uint32
+	Hand       Cards
+	Teams      [6]uint32
+	CardCounts [6]int
+	Finished   [6]bool
+	Played     [6]Cards
+	Finishes   []Finish
+	// Turn is the number of shots and passes so far, see Game.Turns.
+	Turn int
+	// PassesLeft is the number of passes that end the round.
+	PassesLeft int
+}
+
+// ShotFor picks cards from the hand, where cards without a color stand for
+// any card of their number, and makes them a shot on curShot, see
+// Player.PickCards and Rules.ShotFor.
+func (v *TableView) ShotFor(cards Cards, curShot Shot) (shot Shot, err error) {
+	p := &Player{
+		Cards: v.Hand,
+	}
+	if cards, err = p.PickCards(cards); err != nil {
+		return
+	}
+	if shot, err = v.Rules.ShotFor(cards, curShot); err != nil {
+		return
+	}
+	shot.Team = v.Team
+	return
+}
+
+// RegisterStrategy makes a strategy available under name, replacing any
+// strategy registered before under the same name.
+func RegisterStrategy(name string, factory func() Strategy) {
+	strategies[name] = factory
+}
+
+func NewStrategy(name string) (Strategy, error) {
+	factory, ok := strategies[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown strategy %q", name)
+	}
+	return factory(), nil
+}
+
+func StrategyNames() (names []string) {
+	for name := range strategies {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return
+}
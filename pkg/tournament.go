@@ -0,0 +1,243 @@
+package pkg
+
+import (
+	"fmt"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// TournamentOptions sets up a round robin between teams. Every entry plays
+// every other entry on Deals deals, and every deal is played twice with the
+// same hands, the teams trading seats, so neither team is favored by the
+// cards. Deal d is dealt with Seed+d in every pairing.
+type TournamentOptions struct {
+	// Entries are the teams. An entry is a strategy name playing all three
+	// seats of its team, or three names joined by "+" for the seats of the
+	// team, which rotate over the deals.
+	Entries []string
+	Deals   int
+	Seed    int64
+	Rules   *Rules
+	// Workers is the number of games played at once, runtime.NumCPU() if
+	// zero.
+	Workers int
+}
+
+// TournamentResult has the standings from the first to the last entry and
+// the results of every pairing.
+type TournamentResult struct {
+	Seed      int64      `json:"seed"`
+	Deals     int        `json:"deals"`
+	Standings []Standing `json:"standings"`
+	Pairings  []Pairing  `json:"pairings"`
+}
+
+// Standing sums up the hands an entry played. Points are those of
+// HandPoints, scored by the entry's team.
+type Standing struct {
+	Entry   string `json:"entry"`
+	Hands   int    `json:"hands"`
+	Wins    int    `json:"wins"`
+	Points  int    `json:"points"`
+	WinRate Stat   `json:"winRate"`
+}
+
+// Pairing is the result of two entries A and B. A deal is swept by the
+// entry that won it in both seatings, the other deals were decided by the
+// cards.
+type Pairing struct {
+	A       string `json:"a"`
+	B       string `json:"b"`
+	Hands   int    `json:"hands"`
+	WinsA   int    `json:"winsA"`
+	WinsB   int    `json:"winsB"`
+	PointsA int    `json:"pointsA"`
+	PointsB int    `json:"pointsB"`
+	SweepsA int    `json:"sweepsA"`
+	SweepsB int    `json:"sweepsB"`
+	// WinRateA is the share of hands won by A.
+	WinRateA Stat `json:"winRateA"`
+}
+
+type tournamentGame struct {
+	pairing int
+	deal    int
+	// swapped is set when A plays the seats of the second team.
+	swapped bool
+	seats   [6]string
+	result  HandResult
+	err     error
+}
+
+// Tournament plays the round robin of opts.
+func Tournament(opts TournamentOptions) (result TournamentResult, err error) {
+	if len(opts.Entries) < 2 {
+		return result, fmt.Errorf("a tournament needs at least two entries")
+	}
+	if opts.Deals < 1 {
+		return result, fmt.Errorf("a tournament needs at least one deal, got %d", opts.Deals)
+	}
+	if opts.Rules != nil {
+		if err = opts.Rules.Validate(); err != nil {
+			return
+		}
+	}
+	lineups := make([][3]string, len(opts.Entries))
+	seen := make(map[string]bool)
+	for i, entry := range opts.Entries {
+		if seen[entry] {
+			return result, fmt.Errorf("entry %q is listed twice", entry)
+		}
+		seen[entry] = true
+		if lineups[i], err = parseLineup(entry); err != nil {
+			return
+		}
+	}
+	if opts.Seed == 0 {
+		opts.Seed = time.Now().UnixNano()
+	}
+	rules := DefaultRules()
+	if opts.Rules != nil {
+		rules = *opts.Rules
+	}
+	var teams [2][]int
+	for seat, team := range rules.Teams {
+		if team == rules.Teams[0] {
+			teams[0] = append(teams[0], seat)
+		} else {
+			teams[1] = append(teams[1], seat)
+		}
+	}
+
+	var games []*tournamentGame
+	for a := range lineups {
+		for b := a + 1; b < len(lineups); b++ {
+			result.Pairings = append(result.Pairings, Pairing{
+				A: opts.Entries[a],
+				B: opts.Entries[b],
+			})
+			for deal := 0; deal < opts.Deals; deal++ {
+				for _, swapped := range []bool{false, true} {
+					game := &tournamentGame{
+						pairing: len(result.Pairings) - 1,
+						deal:    deal,
+						swapped: swapped,
+					}
+					first, second := lineups[a], lineups[b]
+					if swapped {
+						first, second = second, first
+					}
+					for k := range first {
+						game.seats[teams[0][(k+deal)%3]] = first[k]
+						game.seats[teams[1][(k+deal)%3]] = second[k]
+					}
+					games = append(games, game)
+				}
+			}
+		}
+	}
+	workers := opts.Workers
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+	queue := make(chan *tournamentGame)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for game := range queue {
+				sim := SimOptions{Seats: game.seats, Rules: opts.Rules}
+				game.result, game.err = simulateGame(sim, opts.Seed+int64(game.deal))
+			}
+		}()
+	}
+	for _, game := range games {
+		queue <- game
+	}
+	close(queue)
+	wg.Wait()
+
+	result.Seed = opts.Seed
+	result.Deals = opts.Deals
+	standings := make(map[string]*Standing)
+	wins := make(map[string][]float64)
+	winsA := make([][]float64, len(result.Pairings))
+	won := make(map[[2]int]int)
+	for _, game := range games {
+		if game.err != nil {
+			return result, fmt.Errorf("%s vs %s, deal %d: %v", result.Pairings[game.pairing].A,
+				result.Pairings[game.pairing].B, game.deal, game.err)
+		}
+		p := &result.Pairings[game.pairing]
+		winner, points := HandPoints(game.result.Ranking, rules.Teams)
+		aWon := (winner == rules.Teams[teams[0][0]]) != game.swapped
+		p.Hands++
+		for _, entry := range []string{p.A, p.B} {
+			if standings[entry] == nil {
+				standings[entry] = &Standing{Entry: entry}
+			}
+			s := standings[entry]
+			s.Hands++
+			if aWon == (entry == p.A) {
+				s.Wins++
+				s.Points += points
+				wins[entry] = append(wins[entry], 1)
+			} else {
+				wins[entry] = append(wins[entry], 0)
+			}
+		}
+		key := [2]int{game.pairing, game.deal}
+		if aWon {
+			p.WinsA++
+			p.PointsA += points
+			winsA[game.pairing] = append(winsA[game.pairing], 1)
+			won[key]++
+		} else {
+			p.WinsB++
+			p.PointsB += points
+			winsA[game.pairing] = append(winsA[game.pairing], 0)
+			won[key]--
+		}
+	}
+	for key, n := range won {
+		if n == 2 {
+			result.Pairings[key[0]].SweepsA++
+		} else if n == -2 {
+			result.Pairings[key[0]].SweepsB++
+		}
+	}
+	for i := range result.Pairings {
+		result.Pairings[i].WinRateA = newStat(winsA[i])
+	}
+	for _, entry := range opts.Entries {
+		s := standings[entry]
+		s.WinRate = newStat(wins[entry])
+		result.Standings = append(result.Standings, *s)
+	}
+	sort.SliceStable(result.Standings, func(i, j int) bool {
+		a, b := result.Standings[i], result.Standings[j]
+		if a.WinRate.Mean != b.WinRate.Mean {
+			return a.WinRate.Mean > b.WinRate.Mean
+		}
+		return a.Points > b.Points
+	})
+	return
+}
+
+func parseLineup(entry string) (lineup [3]string, err error) {
+	names := strings.Split(entry, "+")
+	if len(names) != 1 && len(names) != len(lineup) {
+		return lineup, fmt.Errorf("entry %q: expect 1 or %d strategies", entry, len(lineup))
+	}
+	for k := range lineup {
+		lineup[k] = strings.TrimSpace(names[k%len(names)])
+		if _, err = NewStrategy(lineup[k]); err != nil {
+			return lineup, fmt.Errorf("entry %q: %v", entry, err)
+		}
+	}
+	return
+}
@@ -0,0 +1,233 @@
+package pkg
+
+import "sort"
+
+// CardTracker counts the cards of the deck that are not played yet. Made by
+// TableView.Tracker it also knows the seat's own hand and how many cards
+// every other seat holds, so it tells the cards the seat has not seen.
+// Subscribed to a Game it follows the played cards of every hand, and all
+// unplayed cards count as unseen.
+type CardTracker struct {
+	Rules   Rules
+	deck    map[Card]int
+	played  map[Card]int
+	hand    map[Card]int
+	holders [6]int
+}
+
+func NewCardTracker(rules Rules) *CardTracker {
+	t := &CardTracker{
+		Rules: rules,
+		deck:  make(map[Card]int),
+	}
+	for _, card := range t.Rules.Deck() {
+		t.deck[card]++
+	}
+	t.Reset()
+	return t
+}
+
+// Tracker returns a CardTracker of what the seat has seen in this hand.
+func (v *TableView) Tracker() *CardTracker {
+	t := NewCardTracker(v.Rules)
+	for _, played := range v.Played {
+		t.Record(played)
+	}
+	for _, card := range v.Hand {
+		t.hand[card]++
+	}
+	t.holders = v.CardCounts
+	t.holders[v.Seat] = 0
+	return t
+}
+
+// Reset forgets the cards of the last hand.
+func (t *CardTracker) Reset() {
+	t.played = make(map[Card]int)
+	t.hand = make(map[Card]int)
+	t.holders = [6]int{}
+}
+
+// Record counts cards as played.
+func (t *CardTracker) Record(cards Cards) {
+	for _, card := range cards {
+		t.played[card]++
+	}
+}
+
+func (t *CardTracker) OnEvent(e Event) {
+	switch e := e.(type) {
+	case HandDealt:
+		t.Reset()
+		for i, hand := range e.Hands {
+			t.holders[i] = len(hand)
+		}
+	case ShotPlayed:
+		t.Record(e.Shot.Cards)
+		t.holders[e.Seat] -= len(e.Shot.Cards)
+	}
+}
+
+// Remaining returns the number of cards like card that are not played yet,
+// a card without a color counts every card of its number.
+func (t *CardTracker) Remaining(card Card) int {
+	return t.count(t.deck, card) - t.count(t.played, card)
+}
+
+// Unseen is Remaining without the cards of the seat's hand.
+func (t *CardTracker) Unseen(card Card) int {
+	return t.Remaining(card) - t.count(t.hand, card)
+}
+
+func (t *CardTracker) count(counts map[Card]int, card Card) int {
+	if card.Color != "" || card.IsJoker() {
+		return counts[card]
+	}
+	n := 0
+	for color := range mapColorName {
+		n += counts[Card{Num: card.Num, Color: color}]
+	}
+	return n
+}
+
+// Holders returns the number of unseen cards every seat holds.
+func (t *CardTracker) Holders() [6]int {
+	return t.holders
+}
+
+// MaxHeld returns the most cards like card that seat may hold.
+func (t *CardTracker) MaxHeld(seat int, card Card) int {
+	n := t.Unseen(card)
+	if t.holders[seat] < n {
+		n = t.holders[seat]
+	}
+	return n
+}
+
+// UnseenCards lists the cards the seat has not seen, ordered by CardSorter.
+func (t *CardTracker) UnseenCards() (cards Cards) {
+	for card, n := range t.deck {
+		for n -= t.played[card] + t.hand[card]; n > 0; n-- {
+			cards = append(cards, card)
+		}
+	}
+	sort.Sort(CardSorter(cards))
+	return
+}
+
+// Unbeatable reports whether no seat can beat shot with the unseen cards it
+// may hold.
+func (t *CardTracker) Unbeatable(shot Shot) bool {
+	for _, threat := range t.threats() {
+		if _, err := t.Rules.ShotFor(threat, shot); err == nil {
+			return false
+		}
+	}
+	return true
+}
+
+// threats lists the strongest shots of every kind that the unseen cards may
+// form in a single hand: equal cards of every number, bombs, straights,
+// straight flushes, the highest flush of every suit, repeated cards
+// included, and four of a kind and full houses with the highest other
+// cards, jokers included.
+func (t *CardTracker) threats() (threats []Cards) {
+	maxHold := 0
+	for _, n := range t.holders {
+		if n > maxHold {
+			maxHold = n
+		}
+	}
+	add := func(cards Cards) {
+		if len(cards) != 0 && len(cards) <= maxHold {
+			threats = append(threats, cards)
+		}
+	}
+	unseen := t.UnseenCards()
+	same := runs(unseen)
+	sort.SliceStable(same, func(i, j int) bool {
+		return t.Rules.Rank(same[i][0].Num) > t.Rules.Rank(same[j][0].Num)
+	})
+	byNum := make(map[uint32]Cards)
+	var jokers Cards
+	for _, cards := range same {
+		byNum[cards[0].Num] = cards
+		if cards[0].IsJoker() {
+			jokers = append(jokers, cards...)
+		}
+	}
+	// the first other number with at least n cards, the highest first
+	other := func(num uint32, n int) Cards {
+		for _, cards := range same {
+			if cards[0].Num != num && (!cards[0].IsJoker() || t.Rules.JokersInFive) && len(cards) >= n {
+				return cards[:n]
+			}
+		}
+		return nil
+	}
+	for _, cards := range same {
+		num := cards[0].Num
+		for n := 1; n <= len(cards) && n <= 5; n++ {
+			add(cards[:n])
+		}
+		if len(cards) > 5 {
+			add(cards)
+			if len(cards) > maxHold {
+				add(cards[:maxHold])
+			}
+		}
+		if len(cards) >= 4 {
+			if kicker := other(num, 1); kicker != nil {
+				add(append(cards[:4:4], kicker...))
+			}
+		}
+		if len(cards) >= 3 {
+			if pair := other(num, 2); pair != nil {
+				add(append(cards[:3:3], pair...))
+			}
+		}
+	}
+	if len(jokers) != 0 {
+		sort.Sort(sort.Reverse(CardSorter(jokers)))
+		for n := 1; n <= len(jokers); n++ {
+			add(jokers[:n])
+		}
+	}
+	order := t.Rules.straightOrder()
+	for start := 0; start+5 <= len(order); start++ {
+		var straight Cards
+		for _, num := range order[start : start+5] {
+			if cards := byNum[num]; len(cards) != 0 {
+				straight = append(straight, cards[0])
+			}
+		}
+		if len(straight) == 5 {
+			add(straight)
+		}
+		for color := range mapColorName {
+			var flush Cards
+			for _, num := range order[start : start+5] {
+				if card := (Card{Num: num, Color: color}); t.Unseen(card) > 0 {
+					flush = append(flush, card)
+				}
+			}
+			if len(flush) == 5 {
+				add(flush)
+			}
+		}
+	}
+	for color := range mapColorName {
+		var flush Cards
+		for _, cards := range same {
+			for _, card := range cards {
+				if card.Color == color && len(flush) < 5 {
+					flush = append(flush, card)
+				}
+			}
+		}
+		if len(flush) == 5 {
+			add(flush)
+		}
+	}
+	return
+}
@@ -0,0 +1,61 @@
+package tui
+
+import "bufio"
+
+// Key is a key press read from a terminal without line buffering.
+type Key int
+
+const (
+	KeyNone Key = iota
+	KeyLeft
+	KeyRight
+	KeyUp
+	KeyDown
+	KeyToggle
+	KeyPlay
+	KeyPass
+	KeyClear
+)
+
+var mapRuneKey = map[byte]Key{
+	'h':  KeyLeft,
+	'a':  KeyLeft,
+	'l':  KeyRight,
+	'd':  KeyRight,
+	'k':  KeyUp,
+	'w':  KeyUp,
+	'j':  KeyDown,
+	's':  KeyDown,
+	' ':  KeyToggle,
+	'\r': KeyPlay,
+	'\n': KeyPlay,
+	'p':  KeyPass,
+	'c':  KeyClear,
+}
+
+var mapArrowKey = map[byte]Key{
+	'A': KeyUp,
+	'B': KeyDown,
+	'C': KeyRight,
+	'D': KeyLeft,
+}
+
+// ReadKey reads the next key, the arrow keys are read from their escape
+// sequences. Keys without a meaning are KeyNone.
+func ReadKey(r *bufio.Reader) (Key, error) {
+	b, err := r.ReadByte()
+	if err != nil {
+		return KeyNone, err
+	}
+	if b != 0x1b {
+		return mapRuneKey[b], nil
+	}
+	if next, err := r.Peek(2); err != nil || next[0] != '[' {
+		return KeyNone, nil
+	}
+	seq := make([]byte, 2)
+	if _, err = r.Read(seq); err != nil {
+		return KeyNone, err
+	}
+	return mapArrowKey[seq[1]], nil
+}
@@ -0,0 +1,125 @@
+package tui
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"CardGame3V3Go/pkg"
+)
+
+const (
+	escClear   = "\x1b[H\x1b[2J"
+	escReset   = "\x1b[0m"
+	escBold    = "\x1b[1m"
+	escDim     = "\x1b[2m"
+	escReverse = "\x1b[7m"
+	escRed     = "\x1b[31m"
+	escOnGreen = "\x1b[42m"
+	escOnRed   = "\x1b[41m"
+)
+
+var mapColorSymbol = map[pkg.CardColor]string{
+	pkg.SPADE:   "♠",
+	pkg.HEART:   "♥",
+	pkg.CLUB:    "♣",
+	pkg.DIAMOND: "♦",
+}
+
+// Screen is what the player sees at its turn: the six seats with their card
+// counts and last actions, the shot to beat and the hand to pick from.
+type Screen struct {
+	Selector *Selector
+	// Last is the last shot or pass of every seat in this round.
+	Last [6]string
+	// ShotSeat is the seat that played the shot to beat, -1 if none.
+	ShotSeat int
+	Status   string
+}
+
+// Render draws the screen with ANSI escapes.
+func (s *Screen) Render(w io.Writer) {
+	sel := s.Selector
+	view := &sel.View
+	var b strings.Builder
+	b.WriteString(escClear)
+	fmt.Fprintf(&b, "%s3v3%s  you are Player%d of team %d\r\n\r\n", escBold, escReset, view.Seat, view.Team)
+	for i := range view.Teams {
+		marker := " "
+		if i == s.ShotSeat {
+			marker = "*"
+		}
+		you := ""
+		if i == view.Seat {
+			you = " (you)"
+		}
+		state := fmt.Sprintf("%2d cards", view.CardCounts[i])
+		if view.Finished[i] {
+			state = "finished"
+		}
+		fmt.Fprintf(&b, " %s Player%d%-6s team %d  %s  %s\r\n", marker, i, you, view.Teams[i], state, s.Last[i])
+	}
+	b.WriteString("\r\n")
+	if sel.CurShot.Type == pkg.ShotTypePass {
+		b.WriteString("You lead a new round\r\n")
+	} else {
+		friend := ""
+		if sel.CurShot.Team == view.Team {
+			friend = ", your team"
+		}
+		fmt.Fprintf(&b, "Shot to beat: %s by Player%d (team %d%s)\r\n",
+			cardsLabel(sel.CurShot.Cards), s.ShotSeat, sel.CurShot.Team, friend)
+	}
+	b.WriteString("\r\n")
+
+	_, err := sel.Shot()
+	for i, card := range sel.Hand {
+		style := ""
+		switch {
+		case sel.Selected[i] && err == nil:
+			style += escOnGreen
+		case sel.Selected[i]:
+			style += escOnRed
+		case !sel.Playable(i):
+			style += escDim
+		}
+		if i == sel.Cursor {
+			style += escReverse
+		}
+		fmt.Fprintf(&b, "%s%s%s ", style, cardLabel(card), escReset)
+	}
+	b.WriteString("\r\n\r\n")
+	if cards := sel.Cards(); len(cards) == 0 {
+		b.WriteString("Nothing picked")
+	} else if err != nil {
+		fmt.Fprintf(&b, "Picked %s: %v", cardsLabel(cards), err)
+	} else {
+		fmt.Fprintf(&b, "Picked %s: ready to play", cardsLabel(cards))
+	}
+	b.WriteString("\r\n")
+	if s.Status != "" {
+		fmt.Fprintf(&b, "%s%s%s\r\n", escBold, s.Status, escReset)
+	}
+	b.WriteString("\r\n←/→ move  space pick  ↑/↓ pick/drop the number  enter play  p pass  c clear\r\n")
+	io.WriteString(w, b.String())
+}
+
+func cardLabel(card pkg.Card) string {
+	name := card.Name()
+	if name == "0" {
+		name = "10"
+	}
+	label := mapColorSymbol[card.Color] + name
+	if card.Color == pkg.HEART || card.Color == pkg.DIAMOND || card.Num == 22 {
+		return escRed + label
+	}
+	return label
+}
+
+func cardsLabel(cards pkg.Cards) string {
+	labels := make([]string, len(cards))
+	for i, card := range cards {
+		labels[i] = cardLabel(card) + escReset
+	}
+	return strings.Join(labels, " ")
+}
@@ -0,0 +1,114 @@
+package tui
+
+import (
+	"sort"
+
+	"CardGame3V3Go/pkg"
+)
+
+// Selector is the hand shown on the screen with the cursor and the cards
+// picked for the next shot. The hand is ordered by rank and then by suit.
+type Selector struct {
+	View     pkg.TableView
+	CurShot  pkg.Shot
+	Hand     pkg.Cards
+	Cursor   int
+	Selected []bool
+	legal    []pkg.Shot
+}
+
+// NewSelector starts a selection from view's hand against the shot to beat.
+func NewSelector(view pkg.TableView, curShot pkg.Shot) *Selector {
+	hand := view.Hand.Copy()
+	sort.Sort(pkg.CardSorter(hand))
+	sort.SliceStable(hand, func(i, j int) bool {
+		return view.Rules.Rank(hand[i].Num) < view.Rules.Rank(hand[j].Num)
+	})
+	return &Selector{
+		View:     view,
+		CurShot:  curShot,
+		Hand:     hand,
+		Selected: make([]bool, len(hand)),
+		legal:    view.Rules.LegalShots(hand, curShot),
+	}
+}
+
+// Move moves the cursor by delta cards, wrapping around the hand.
+func (s *Selector) Move(delta int) {
+	if len(s.Hand) == 0 {
+		return
+	}
+	s.Cursor = ((s.Cursor+delta)%len(s.Hand) + len(s.Hand)) % len(s.Hand)
+}
+
+// Toggle picks or drops the card under the cursor.
+func (s *Selector) Toggle() {
+	if len(s.Hand) != 0 {
+		s.Selected[s.Cursor] = !s.Selected[s.Cursor]
+	}
+}
+
+func (s *Selector) Clear() {
+	for i := range s.Selected {
+		s.Selected[i] = false
+	}
+}
+
+// Cards returns the picked cards.
+func (s *Selector) Cards() (cards pkg.Cards) {
+	for i, card := range s.Hand {
+		if s.Selected[i] {
+			cards = append(cards, card)
+		}
+	}
+	return
+}
+
+// Playable reports whether the card at i is picked or may be added to the
+// picked cards on the way to some legal shot.
+func (s *Selector) Playable(i int) bool {
+	cards := s.Cards()
+	if !s.Selected[i] {
+		cards = append(cards, s.Hand[i])
+	}
+	for _, shot := range s.legal {
+		if contains(shot.Cards, cards) {
+			return true
+		}
+	}
+	return false
+}
+
+// Shot returns the picked cards as a shot, or why they cannot be played.
+func (s *Selector) Shot() (pkg.Shot, error) {
+	return s.View.ShotFor(s.Cards(), s.CurShot)
+}
+
+// contains reports whether every card of sub is in cards, counting equal
+// cards.
+func contains(cards, sub pkg.Cards) bool {
+	counts := make(map[pkg.Card]int)
+	for _, card := range cards {
+		counts[card]++
+	}
+	for _, card := range sub {
+		if counts[card]--; counts[card] < 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// PickNumber picks or drops all cards with the number of the card under the
+// cursor.
+func (s *Selector) PickNumber(pick bool) {
+	if len(s.Hand) == 0 {
+		return
+	}
+	num := s.Hand[s.Cursor].Num
+	for i, card := range s.Hand {
+		if card.Num == num {
+			s.Selected[i] = pick
+		}
+	}
+}
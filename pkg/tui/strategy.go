@@ -0,0 +1,86 @@
+// Package tui plays a seat in a full-screen terminal UI: the table is
+// redrawn with ANSI escapes at every turn and the shot is picked card by
+// card with the keyboard, with the cards that can still make a legal shot
+// highlighted. The terminal must send keys unbuffered, e.g. after
+// "stty -icanon -echo".
+package tui
+
+import (
+	"bufio"
+	"io"
+
+	"CardGame3V3Go/pkg"
+)
+
+// Strategy asks for every shot on the screen. It is also an Observer, and
+// shows the last shots of the other seats once it subscribed to the game.
+type Strategy struct {
+	In     *bufio.Reader
+	Out    io.Writer
+	screen Screen
+}
+
+// NewStrategy reads keys from in and draws on out.
+func NewStrategy(in io.Reader, out io.Writer) *Strategy {
+	s := &Strategy{
+		In:  bufio.NewReader(in),
+		Out: out,
+	}
+	s.screen.ShotSeat = -1
+	return s
+}
+
+func (s *Strategy) OnEvent(e pkg.Event) {
+	switch e := e.(type) {
+	case pkg.HandDealt:
+		s.screen.Last = [6]string{}
+		s.screen.ShotSeat = -1
+	case pkg.ShotPlayed:
+		s.screen.Last[e.Seat] = cardsLabel(e.Shot.Cards)
+		s.screen.ShotSeat = e.Seat
+	case pkg.Passed:
+		s.screen.Last[e.Seat] = "pass"
+	case pkg.RoundReset:
+		s.screen.Last = [6]string{}
+		s.screen.ShotSeat = -1
+	}
+}
+
+func (s *Strategy) NextShot(view pkg.TableView, curShot pkg.Shot) pkg.Shot {
+	sel := NewSelector(view, curShot)
+	s.screen.Selector = sel
+	s.screen.Status = ""
+	for {
+		s.screen.Render(s.Out)
+		key, err := ReadKey(s.In)
+		if err != nil {
+			panic(err)
+		}
+		s.screen.Status = ""
+		switch key {
+		case KeyLeft:
+			sel.Move(-1)
+		case KeyRight:
+			sel.Move(1)
+		case KeyUp:
+			sel.PickNumber(true)
+		case KeyDown:
+			sel.PickNumber(false)
+		case KeyToggle:
+			sel.Toggle()
+		case KeyClear:
+			sel.Clear()
+		case KeyPass:
+			if curShot.Type != pkg.ShotTypePass {
+				return pkg.Shot{Team: view.Team}
+			}
+			s.screen.Status = "You lead this round and cannot pass!"
+		case KeyPlay:
+			shot, err := sel.Shot()
+			if err == nil {
+				return shot
+			}
+			s.screen.Status = "Oops, " + err.Error() + "!"
+		}
+	}
+}
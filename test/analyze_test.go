@@ -0,0 +1,49 @@
+package test
+
+import (
+	"bytes"
+	"testing"
+
+	"CardGame3V3Go/pkg"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAnalyzer(t *testing.T) {
+	var buf bytes.Buffer
+	g := pkg.NewGame(pkg.GameOptions{Seed: 9})
+	g.Players[0].Strategy = pkg.EasyStrategy{Mistakes: 1}
+	recorder := pkg.RecordReplay(&g, &buf, [6]string{"human", "normal", "normal", "normal", "normal", "normal"})
+	g.Start()
+	_, err := g.Play()
+	require.NoError(t, err)
+	require.NoError(t, recorder.Err())
+	replays, err := pkg.ReadReplays(&buf)
+	require.NoError(t, err)
+	r := &replays[0]
+
+	a := pkg.NewAnalyzer()
+	a.Search.Iterations = 4
+	a.Threshold = 0.5
+	analysis, err := a.Analyze(r)
+	require.NoError(t, err)
+	require.Equal(t, []int{0}, analysis.Seats)
+	require.NotZero(t, analysis.Decisions)
+	require.NotEmpty(t, analysis.Mistakes)
+	for _, m := range analysis.Mistakes {
+		require.Equal(t, 0, m.Seat)
+		require.True(t, m.Loss >= a.Threshold)
+		require.InDelta(t, m.BetterWin-m.PlayedWin, m.Loss, 1e-9)
+		require.NotEqual(t, m.Played, m.Better)
+		require.Equal(t, r.Moves[m.Turn-1].Shot().Cards, m.Played.Cards)
+	}
+
+	a.Threshold = 1.1
+	a.Seats = []int{1}
+	analysis, err = a.Analyze(r)
+	require.NoError(t, err)
+	require.Empty(t, analysis.Mistakes)
+
+	a.Seats = []int{6}
+	_, err = a.Analyze(r)
+	require.Error(t, err)
+}
@@ -224,3 +224,104 @@ func TestCards_TestLarger(t *testing.T) {
 	require.NoError(t, err)
 	require.True(t, isLarger)
 }
+
+func TestParseCards(t *testing.T) {
+	var deck pkg.Cards
+	for num := uint32(3); num <= 15; num++ {
+		for _, color := range []pkg.CardColor{pkg.SPADE, pkg.HEART, pkg.CLUB, pkg.DIAMOND} {
+			deck = append(deck, pkg.Card{Num: num, Color: color})
+		}
+	}
+	deck = append(deck, pkg.Card{Num: 21}, pkg.Card{Num: 22})
+	for _, card := range deck {
+		parsed, err := pkg.ParseCard(card.String())
+		require.NoError(t, err)
+		require.Equal(t, card, parsed)
+	}
+	parsed, err := pkg.ParseCards(deck.String())
+	require.NoError(t, err)
+	require.Equal(t, deck, parsed)
+
+	expected := pkg.Cards{
+		pkg.Card{Num: 3, Color: pkg.SPADE},
+		pkg.Card{Num: 10, Color: pkg.HEART},
+		pkg.Card{Num: 14, Color: pkg.DIAMOND},
+		pkg.Card{Num: 21},
+	}
+	for _, str := range []string{"S3 H0 DA 小", "♠3♥10♦A BJ", "s3,hT,da,bj"} {
+		parsed, err = pkg.ParseCards(str)
+		require.NoError(t, err)
+		require.Equal(t, expected, parsed)
+	}
+
+	parsed, err = pkg.ParseCards("3345")
+	require.NoError(t, err)
+	require.Equal(t, pkg.Cards{{Num: 3}, {Num: 3}, {Num: 4}, {Num: 5}}, parsed)
+
+	for _, str := range []string{"S", "SH3", "S小", "X3", "1"} {
+		_, err = pkg.ParseCards(str)
+		require.Error(t, err, str)
+	}
+}
+
+func TestCards_Delete(t *testing.T) {
+	hand := pkg.CardStrToCards("S3 H3 H3 C4")
+	require.Equal(t, pkg.CardStrToCards("S3 H3 C4"), hand.Delete(pkg.Card{Num: 3, Color: pkg.HEART}))
+	require.Equal(t, pkg.CardStrToCards("H3 H3 C4"), hand.Delete(pkg.Card{Num: 3, Color: pkg.SPADE}))
+	require.Equal(t, hand, hand.Delete(pkg.Card{Num: 3, Color: pkg.DIAMOND}))
+	require.True(t, hand.Contains(pkg.Card{Num: 4, Color: pkg.CLUB}))
+	require.False(t, hand.Contains(pkg.Card{Num: 4, Color: pkg.SPADE}))
+
+	p := pkg.Player{Cards: hand.Copy()}
+	picked, err := p.PickCards(pkg.CardStrToCards("3 S3"))
+	require.NoError(t, err)
+	require.Equal(t, pkg.CardStrToCards("S3 H3"), picked)
+	p.RemoveCards(picked)
+	require.Equal(t, []pkg.Card(pkg.CardStrToCards("H3 C4")), p.Cards)
+	_, err = p.PickCards(pkg.CardStrToCards("S3"))
+	require.Error(t, err)
+}
+
+func TestCards_RankFive(t *testing.T) {
+	for str, category := range map[string]pkg.FiveCardCategory{
+		"SJ HQ CK DA S2": pkg.FiveCardStraight,
+		"S3 S3 S3 S5 S7": pkg.FiveCardFlush,
+		"S3 S3 S3 S7 S7": pkg.FiveCardFullHouse,
+		"小 小 小 S7 H7":    pkg.FiveCardFullHouse,
+		"大 大 S7 H7 C7":   pkg.FiveCardFullHouse,
+		"S7 H7 C7 D7 小":  pkg.FiveCardFourOfAKind,
+		"H0 HJ HQ HK HA": pkg.FiveCardStraightFlush,
+		"S2 S2 H2 C2 D2": pkg.FiveCardFiveOfAKind,
+		"小 小 小 小 小":      pkg.FiveCardFiveOfAKind,
+	} {
+		rank, err := pkg.CardStrToCards(str).RankFive()
+		require.NoError(t, err, str)
+		require.Equal(t, category, rank.Category, str)
+	}
+	for _, str := range []string{
+		"SA H2 S3 S4 S5", "HK HA H2 小 大", "S3 H3 C3 S5 S7", "S3 S3 H5 H5 S7", "S3 S4 S5 S6",
+	} {
+		_, err := pkg.CardStrToCards(str).RankFive()
+		require.Error(t, err, str)
+	}
+
+	for _, c := range []struct {
+		a, b string
+		cmp  int
+	}{
+		{"S3 S5 S7 S9 SK", "H3 H5 H7 H9 HQ", 1},
+		{"S3 S5 S7 S9 SK", "H4 H5 H7 H9 HK", -1},
+		{"S3 S5 S7 S9 SK", "H3 H5 H7 H9 HK", 0},
+		{"S4 H4 C4 D3 D3", "S3 H3 C3 D2 D2", 1},
+		{"S3 H3 C3 C3 DA", "S2 H4 D4 C4 C4", -1},
+		{"S9 H0 CJ DQ SK", "H3 H5 H7 H9 HK", -1},
+		{"SJ HQ CK DA S2", "S0 HJ CQ DK SA", 1},
+		{"S3 S4 S5 S6 S7", "SA SA HA CA 小", 1},
+	} {
+		cmp, err := pkg.CompareFive(pkg.CardStrToCards(c.a), pkg.CardStrToCards(c.b))
+		require.NoError(t, err)
+		require.Equal(t, c.cmp, cmp, "%s vs %s", c.a, c.b)
+		shot := pkg.Shot{Cards: pkg.CardStrToCards(c.b), Type: pkg.ShotTypeFive}
+		require.Equal(t, c.cmp > 0, shot.CheckLarger(pkg.CardStrToCards(c.a)))
+	}
+}
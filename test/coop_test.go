@@ -0,0 +1,51 @@
+package test
+
+import (
+	"testing"
+
+	"CardGame3V3Go/pkg"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCoopStrategy(t *testing.T) {
+	s, err := pkg.NewStrategy("coop")
+	require.NoError(t, err)
+	single := func(str string, team uint32) pkg.Shot {
+		return pkg.Shot{Cards: pkg.CardStrToCards(str), Type: pkg.ShotTypeOne, Team: team}
+	}
+	cases := []struct {
+		name   string
+		hand   string
+		counts [6]int
+		cur    pkg.Shot
+		want   string
+	}{
+		{"feed a teammate", "S3 S4 S5 S6 D7 S9", [6]int{6, 8, 1, 8, 8, 8}, pkg.Shot{}, "S3"},
+		{"lead past an opponent", "S3 H9 H9 SK", [6]int{4, 1, 8, 8, 8, 8}, pkg.Shot{}, "H9 H9"},
+		{"block an opponent", "S4 S2 大", [6]int{3, 1, 5, 5, 5, 5}, single("S3", 2), "大"},
+		{"let a teammate out", "S4 S2 大", [6]int{3, 8, 2, 8, 8, 8}, single("S3", 1), "pass"},
+		{"go out", "S9 H9", [6]int{2, 8, 8, 8, 8, 8}, pkg.Shot{Cards: pkg.CardStrToCards("S3 H3"), Type: pkg.ShotTypeTwo, Team: 1}, "S9 H9"},
+	}
+	for _, c := range cases {
+		view := pkg.TableView{
+			Rules:      pkg.DefaultRules(),
+			Team:       1,
+			Hand:       pkg.CardStrToCards(c.hand),
+			Teams:      pkg.DefaultRules().Teams,
+			CardCounts: c.counts,
+		}
+		shot := s.NextShot(view, c.cur)
+		require.Equal(t, c.want, shot.String(), c.name)
+		require.Equal(t, uint32(1), shot.Team, c.name)
+	}
+
+	normal := pkg.NormalStrategy{}
+	view := pkg.TableView{
+		Rules:      pkg.DefaultRules(),
+		Team:       1,
+		Hand:       pkg.CardStrToCards("S3 S4 S5 S6 D7 S9"),
+		Teams:      pkg.DefaultRules().Teams,
+		CardCounts: [6]int{6, 8, 8, 8, 8, 8},
+	}
+	require.Equal(t, normal.NextShot(view, pkg.Shot{}), s.NextShot(view, pkg.Shot{}))
+}
@@ -0,0 +1,148 @@
+package test
+
+import (
+	"math/rand"
+	"testing"
+
+	"CardGame3V3Go/pkg"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGame_SeededDeal(t *testing.T) {
+	g1 := pkg.NewGame(pkg.GameOptions{Seed: 42})
+	g1.AssignCards()
+	g2 := pkg.NewGame(pkg.GameOptions{Seed: 42})
+	g2.AssignCards()
+	require.Equal(t, int64(42), g1.Seed)
+	for i := range g1.Players {
+		require.Len(t, g1.Players[i].Cards, 27)
+		require.Equal(t, g1.Players[i].Cards, g2.Players[i].Cards)
+	}
+
+	g3 := pkg.NewGame(pkg.GameOptions{Source: rand.NewSource(42)})
+	g3.AssignCards()
+	require.Equal(t, g1.Players[0].Cards, g3.Players[0].Cards)
+
+	g4 := pkg.NewGame(pkg.GameOptions{Seed: 43})
+	g4.AssignCards()
+	require.NotEqual(t, g1.Players[0].Cards, g4.Players[0].Cards)
+
+	require.NotZero(t, pkg.NewGame(pkg.GameOptions{}).Seed)
+}
+
+func TestGame_Apply(t *testing.T) {
+	g := pkg.NewGame(pkg.GameOptions{Seed: 7})
+	g.Start()
+	cur := g.CurrentPlayer()
+	require.Error(t, g.Apply((cur+1)%6, pkg.Shot{}))
+	require.Error(t, g.Apply(cur, pkg.Shot{}))
+	require.NotEmpty(t, g.LegalShots(cur))
+
+	notInHand := pkg.Shot{Cards: pkg.Cards{pkg.Card{Num: 3}}, Type: pkg.ShotTypeOne}
+	require.Error(t, g.Apply(cur, notInHand))
+
+	for turn := 0; !g.IsFinished(); turn++ {
+		require.Less(t, turn, 10000)
+		cur = g.CurrentPlayer()
+		shot := g.Players[cur].NextShot(g.View(cur), g.CurrentShot())
+		require.NoError(t, g.Apply(cur, shot))
+	}
+	require.NotZero(t, g.Winner())
+	require.Equal(t, pkg.ErrGameOver, g.Apply(g.CurrentPlayer(), pkg.Shot{}))
+}
+
+func TestGame_PlayStrategies(t *testing.T) {
+	_, err := pkg.NewStrategy("no-such-bot")
+	require.Error(t, err)
+
+	calls := 0
+	pkg.RegisterStrategy("counting", func() pkg.Strategy {
+		return pkg.StrategyFunc(func(view pkg.TableView, curShot pkg.Shot) pkg.Shot {
+			calls++
+			require.Len(t, view.Hand, view.CardCounts[view.Seat])
+			return pkg.NormalStrategy{}.NextShot(view, curShot)
+		})
+	})
+	require.Contains(t, pkg.StrategyNames(), "counting")
+
+	g := pkg.NewGame(pkg.GameOptions{Seed: 11})
+	g.Players[3].Strategy, err = pkg.NewStrategy("counting")
+	require.NoError(t, err)
+	g.Start()
+	_, err = g.Result()
+	require.Error(t, err)
+	result, err := g.Play()
+	require.NoError(t, err)
+	require.True(t, g.IsFinished())
+	require.NotZero(t, calls)
+
+	require.Equal(t, g.Winner(), result.Winner)
+	require.Equal(t, g.Turns(), result.Turns)
+	require.Len(t, result.Ranking, 6)
+	require.GreaterOrEqual(t, len(result.Finished), 3)
+	for i, finish := range result.Finished {
+		require.Equal(t, finish.Seat, result.Ranking[i])
+		require.Empty(t, result.Remaining[finish.Seat])
+		require.LessOrEqual(t, finish.Turn, result.Turns)
+		if i > 0 {
+			require.Greater(t, finish.Turn, result.Finished[i-1].Turn)
+		}
+	}
+	last := result.Finished[len(result.Finished)-1]
+	require.Equal(t, result.Turns, last.Turn)
+	require.Equal(t, result.Winner, g.Players[last.Seat].Team)
+}
+
+func TestGame_Events(t *testing.T) {
+	g := pkg.NewGame(pkg.GameOptions{Seed: 7})
+	var events []pkg.Event
+	g.Subscribe(pkg.ObserverFunc(func(e pkg.Event) {
+		events = append(events, e)
+	}))
+	ch := make(chan pkg.Event, 10000)
+	g.Subscribe(pkg.ChanObserver(ch))
+	g.Start()
+	result, err := g.Play()
+	require.NoError(t, err)
+	require.Len(t, ch, len(events))
+
+	dealt, ok := events[0].(pkg.HandDealt)
+	require.True(t, ok)
+	require.Len(t, dealt.Hands[dealt.Leader], 27)
+	over, ok := events[len(events)-1].(pkg.HandOver)
+	require.True(t, ok)
+	require.Equal(t, result, over.Result)
+
+	// leader is the seat expected to lead the next round, -1 within rounds
+	turns, leader := 0, dealt.Leader
+	checkLeader := func(seat int) {
+		if leader >= 0 {
+			require.Equal(t, leader, seat)
+			leader = -1
+		}
+	}
+	var finished []pkg.Finish
+	for _, e := range events[1 : len(events)-1] {
+		switch e := e.(type) {
+		case pkg.ShotPlayed:
+			checkLeader(e.Seat)
+			turns++
+			require.Equal(t, turns, e.Turn)
+			require.Equal(t, g.Players[e.Seat].Team, e.Shot.Team)
+		case pkg.Passed:
+			require.Equal(t, -1, leader)
+			turns++
+			require.Equal(t, turns, e.Turn)
+		case pkg.RoundReset:
+			require.Equal(t, turns, e.Turn)
+			leader = e.Leader
+		case pkg.PlayerFinished:
+			finished = append(finished, e.Finish)
+			require.Equal(t, len(finished), e.Place)
+		default:
+			t.Fatalf("unexpected event %#v", e)
+		}
+	}
+	require.Equal(t, result.Turns, turns)
+	require.Equal(t, result.Finished, finished)
+}
@@ -0,0 +1,92 @@
+package test
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+
+	"CardGame3V3Go/pkg"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHints(t *testing.T) {
+	view := pkg.TableView{
+		Rules:      pkg.DefaultRules(),
+		Team:       1,
+		Teams:      pkg.DefaultRules().Teams,
+		Hand:       pkg.CardStrToCards("S3 S4 S5 S6 S7 H9 H9 CK"),
+		CardCounts: [6]int{8, 2, 9, 9, 9, 9},
+	}
+	hints := pkg.Hints(view, pkg.Shot{Cards: pkg.CardStrToCards("D8"), Type: pkg.ShotTypeOne, Team: 2})
+	require.Equal(t, []string{"CK", "S3 S4 S5 S6 S7", "H9", "pass"}, hintStrings(hints))
+	require.Contains(t, hints[0].Reasons, "keeps your straight flush")
+	require.Contains(t, hints[0].Reasons, "stops Player1 who has 2 cards left")
+	require.Contains(t, hints[1].Reasons, "spends your straight flush")
+	require.Contains(t, hints[2].Reasons, "breaks your pair H9 H9")
+	require.Contains(t, hints[3].Reasons, "Player1 has 2 cards left")
+
+	view.CardCounts = [6]int{8, 9, 2, 9, 9, 9}
+	hints = pkg.Hints(view, pkg.Shot{Cards: pkg.CardStrToCards("D8"), Type: pkg.ShotTypeOne, Team: 1})
+	require.Equal(t, "pass", hints[0].Shot.String())
+	require.Equal(t, []string{"lets your partner's shot through", "partner Player2 has 2 cards left"}, hints[0].Reasons)
+	hints = pkg.Hints(view, pkg.Shot{})
+	require.Equal(t, "H9 H9", hints[0].Shot.String())
+	require.Contains(t, hints[0].Reasons, "partner Player2 has 2 cards left and may follow")
+
+	view.Hand = pkg.CardStrToCards("S9 H9")
+	hints = pkg.Hints(view, pkg.Shot{Cards: pkg.CardStrToCards("S3 H3"), Type: pkg.ShotTypeTwo, Team: 2})
+	require.Equal(t, []string{"S9 H9", "pass"}, hintStrings(hints))
+	require.Equal(t, []string{"goes out"}, hints[0].Reasons)
+}
+
+func hintStrings(hints []pkg.Hint) (strs []string) {
+	for _, hint := range hints {
+		strs = append(strs, hint.Shot.String())
+	}
+	return
+}
+
+func TestHumanStrategy_Hint(t *testing.T) {
+	view := pkg.TableView{
+		Rules: pkg.DefaultRules(),
+		Team:  1,
+		Teams: pkg.DefaultRules().Teams,
+		Hand:  pkg.CardStrToCards("S3 H3 SK"),
+	}
+	var out bytes.Buffer
+	s := pkg.NewHumanStrategy(strings.NewReader("hint\nS3 H3\n"), &out)
+	require.Equal(t, "S3 H3", s.NextShot(view, pkg.Shot{}).String())
+	require.Contains(t, out.String(), "1. S3 H3: plays your planned pair")
+
+	out.Reset()
+	s = pkg.NewHumanStrategy(strings.NewReader("hint\nSK\n"), &out)
+	s.Hints = false
+	require.Equal(t, "SK", s.NextShot(view, pkg.Shot{}).String())
+	require.Contains(t, out.String(), "Hints are off.")
+	require.NotContains(t, out.String(), "1. ")
+}
+
+func TestHumanStrategy_InputEnds(t *testing.T) {
+	view := pkg.TableView{
+		Rules: pkg.DefaultRules(),
+		Team:  1,
+		Teams: pkg.DefaultRules().Teams,
+		Hand:  pkg.CardStrToCards("S3 H3 SK"),
+	}
+	var out bytes.Buffer
+	in := bufio.NewReader(strings.NewReader("S3\nSK"))
+	a := pkg.NewHumanStrategy(in, &out)
+	b := pkg.NewHumanStrategy(in, &out)
+	require.Equal(t, "S3", a.NextShot(view, pkg.Shot{}).String())
+	require.Equal(t, "SK", b.NextShot(view, pkg.Shot{}).String())
+	require.NoError(t, b.Err)
+
+	shot := b.NextShot(view, pkg.Shot{})
+	require.Equal(t, io.EOF, b.Err)
+	require.NoError(t, view.Rules.CheckShot(view.Hand, pkg.Shot{}, shot))
+	cur := pkg.Shot{Cards: pkg.CardStrToCards("D5"), Type: pkg.ShotTypeOne, Team: 2}
+	require.Equal(t, pkg.ShotTypePass, a.NextShot(view, cur).Type)
+	require.Equal(t, io.EOF, a.Err)
+}
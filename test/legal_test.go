@@ -0,0 +1,71 @@
+package test
+
+import (
+	"testing"
+
+	"CardGame3V3Go/pkg"
+	"github.com/stretchr/testify/require"
+)
+
+func shotStrings(shots []pkg.Shot) (strs []string) {
+	for _, shot := range shots {
+		strs = append(strs, shot.Cards.String())
+	}
+	return
+}
+
+func TestLegalShots_Groups(t *testing.T) {
+	hand := pkg.CardStrToCards("S3 S3 H3 S4 小 小")
+	shots := pkg.LegalShots(hand, pkg.Shot{})
+	require.Equal(t, []string{
+		"S3", "H3", "S4", "小",
+		"S3 S3", "S3 H3", "小 小",
+		"S3 S3 H3",
+		"S3 S3 H3 小 小",
+	}, shotStrings(shots))
+
+	cur := pkg.Shot{Cards: pkg.CardStrToCards("D3"), Type: pkg.ShotTypeOne}
+	require.Equal(t, []string{"S4", "小"}, shotStrings(pkg.LegalShots(hand, cur)))
+
+	cur = pkg.Shot{Cards: pkg.CardStrToCards("D4 D4"), Type: pkg.ShotTypeTwo}
+	require.Equal(t, []string{"小 小"}, shotStrings(pkg.LegalShots(hand, cur)))
+}
+
+func TestLegalShots_Five(t *testing.T) {
+	hand := pkg.CardStrToCards("S3 S4 S5 S6 S7 H7 H7")
+	shots := pkg.LegalShots(hand, pkg.Shot{Type: pkg.ShotTypeFive, Cards: pkg.CardStrToCards("C3 D4 H5 S6 D7")})
+	require.Equal(t, []string{
+		"S3 S4 S5 S6 S7",
+		"S3 S4 S5 S6 S7",
+	}, shotStrings(shots))
+	require.Equal(t, pkg.ShotTypeFive, shots[0].Type)
+	require.Equal(t, pkg.ShotTypeBomb, shots[1].Type)
+
+	hand = pkg.CardStrToCards("S3 H4 S5 C6 D7 D8 D8 D8 H8")
+	shots = pkg.LegalShots(hand, pkg.Shot{Type: pkg.ShotTypeFive, Cards: pkg.CardStrToCards("C3 D4 H5 S6 D7")})
+	require.Equal(t, []string{
+		"H4 S5 C6 D7 H8",
+		"H4 S5 C6 D7 D8",
+		"S3 H8 D8 D8 D8",
+		"H4 H8 D8 D8 D8",
+		"S5 H8 D8 D8 D8",
+		"C6 H8 D8 D8 D8",
+		"D7 H8 D8 D8 D8",
+		"H8 D8 D8 D8",
+	}, shotStrings(shots))
+}
+
+func TestLegalShots_FullHand(t *testing.T) {
+	g := pkg.NewGame(pkg.GameOptions{Seed: 3})
+	g.Start()
+	cur := g.CurrentPlayer()
+	shots := g.LegalShots(cur)
+	require.NotEmpty(t, shots)
+	seen := make(map[string]bool)
+	for _, shot := range shots {
+		key := shot.Cards.String()
+		require.False(t, seen[key], key)
+		seen[key] = true
+		require.NoError(t, g.Players[cur].CheckShot(pkg.Shot{}, shot))
+	}
+}
@@ -0,0 +1,51 @@
+package test
+
+import (
+	"testing"
+
+	"CardGame3V3Go/pkg"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEasyStrategy(t *testing.T) {
+	g := pkg.NewGame(pkg.GameOptions{Seed: 4})
+	g.Start()
+	careful := pkg.EasyStrategy{}
+	clumsy := pkg.EasyStrategy{Mistakes: 1}
+	normal := pkg.NormalStrategy{}
+	leads, follows := 0, 0
+	for !g.IsFinished() {
+		seat := g.CurrentPlayer()
+		view, cur := g.View(seat), g.CurrentShot()
+		require.Equal(t, normal.NextShot(view, cur), careful.NextShot(view, cur))
+		shot := clumsy.NextShot(view, cur)
+		require.Equal(t, shot, clumsy.NextShot(view, cur))
+		if want := normal.NextShot(view, cur); shot.String() != want.String() {
+			if cur.Type == pkg.ShotTypePass {
+				leads++
+			} else {
+				follows++
+			}
+		}
+		require.NoError(t, g.Apply(seat, shot))
+	}
+	require.NotZero(t, leads)
+	require.NotZero(t, follows)
+}
+
+func TestHardStrategy(t *testing.T) {
+	s := pkg.NewHardStrategy()
+	s.Search.Iterations = 2
+
+	view := endgameView(1, "S2 H2 S3", "C2 小 大 D5 D6", [6]int{3, 2, 2, 1})
+	require.Equal(t, "S2 H2", s.NextShot(view, pkg.Shot{}).String())
+	view = endgameView(1, "S2 H2", "C2 小 大 D5 D6", [6]int{2, 2, 2, 1})
+	pair := pkg.Shot{Cards: pkg.CardStrToCards("S3 H3"), Type: pkg.ShotTypeTwo, Team: 2}
+	require.Equal(t, "S2 H2", s.NextShot(view, pair).String())
+
+	g := pkg.NewGame(pkg.GameOptions{Seed: 8})
+	g.Players[0].Strategy = s
+	g.Start()
+	_, err := g.Play()
+	require.NoError(t, err)
+}
@@ -0,0 +1,91 @@
+package test
+
+import (
+	"testing"
+
+	"CardGame3V3Go/pkg"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandPoints(t *testing.T) {
+	teams := [6]uint32{1, 2, 1, 2, 1, 2}
+	cases := []struct {
+		ranking []int
+		winner  uint32
+		points  int
+	}{
+		{[]int{0, 2, 4, 1, 3, 5}, 1, 3},
+		{[]int{1, 0, 3, 5, 2, 4}, 2, 2},
+		{[]int{0, 1, 2, 3, 5, 4}, 2, 1},
+		{[]int{5, 0, 2, 1, 4, 3}, 1, 1},
+	}
+	for _, c := range cases {
+		winner, points := pkg.HandPoints(c.ranking, teams)
+		require.Equal(t, c.winner, winner, "%v", c.ranking)
+		require.Equal(t, c.points, points, "%v", c.ranking)
+	}
+}
+
+func TestMatch_Tribute(t *testing.T) {
+	oneDeck := pkg.DefaultRules()
+	oneDeck.Decks = 1
+	for _, opts := range []pkg.GameOptions{
+		{Seed: 3},
+		{Seed: 17, Rules: &oneDeck},
+		{Seed: 51, Rules: &oneDeck},
+	} {
+		m := pkg.NewMatch(opts, 10)
+		_, err := m.FinishHand()
+		require.Error(t, err)
+		hand := len(m.Game.Rules.Deck()) / len(m.Game.Players)
+		for len(m.Hands) < 3 {
+			tributes := m.StartHand()
+			last := len(m.Hands) - 1
+			if last >= 0 {
+				require.Len(t, tributes, m.Hands[last].Points)
+			}
+			for i := range m.Game.Players {
+				require.Len(t, m.Game.Players[i].Cards, hand)
+			}
+			for _, tribute := range tributes {
+				require.NotEqual(t, m.Game.Players[tribute.From].Team, m.Game.Players[tribute.To].Team)
+				require.True(t, pkg.Cards(m.Game.Players[tribute.To].Cards).Contains(tribute.Card))
+				require.True(t, pkg.Cards(m.Game.Players[tribute.From].Cards).Contains(tribute.Return))
+			}
+			if len(tributes) != 0 {
+				leader := tributes[0]
+				for _, tribute := range tributes {
+					if m.Game.Rules.Rank(tribute.Card.Num) > m.Game.Rules.Rank(leader.Card.Num) {
+						leader = tribute
+					}
+				}
+				require.Equal(t, leader.From, m.Game.CurrentPlayer(), opts.Seed)
+			}
+			_, err := m.Game.Play()
+			require.NoError(t, err)
+			_, err = m.FinishHand()
+			require.NoError(t, err)
+		}
+	}
+}
+
+func TestMatch_Play(t *testing.T) {
+	play := func() *pkg.Match {
+		m := pkg.NewMatch(pkg.GameOptions{Seed: 11}, 10)
+		winner, err := m.Play()
+		require.NoError(t, err)
+		require.Equal(t, winner, m.Winner())
+		require.GreaterOrEqual(t, m.Scores[winner], 10)
+		return m
+	}
+	m1, m2 := play(), play()
+	require.Equal(t, m1.Scores, m2.Scores)
+	require.Equal(t, m1.Hands, m2.Hands)
+
+	var total int
+	for _, hand := range m1.Hands {
+		require.Len(t, hand.Ranking, 6)
+		total += hand.Points
+	}
+	require.Equal(t, m1.Scores[1]+m1.Scores[2], total)
+}
@@ -0,0 +1,74 @@
+package test
+
+import (
+	"testing"
+
+	"CardGame3V3Go/pkg"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewGameAt(t *testing.T) {
+	g := pkg.NewGame(pkg.GameOptions{Seed: 8})
+	g.Start()
+	for g.Turns() < 40 {
+		cur := g.CurrentPlayer()
+		require.NoError(t, g.Apply(cur, g.Players[cur].NextShot(g.View(cur), g.CurrentShot())))
+	}
+	var hands [6]pkg.Cards
+	for i := range hands {
+		hands[i] = pkg.Cards(g.Players[i].Cards).Copy()
+	}
+	view := g.View(g.CurrentPlayer())
+	require.Equal(t, 40, view.Turn)
+	played := 0
+	for i := range view.Played {
+		played += len(view.Played[i])
+		require.Equal(t, 27, len(view.Played[i])+view.CardCounts[i])
+	}
+	require.NotZero(t, played)
+
+	copied := pkg.NewGameAt(view, g.CurrentShot(), hands)
+	want, err := g.Play()
+	require.NoError(t, err)
+	got, err := copied.Play()
+	require.NoError(t, err)
+	require.Equal(t, want, got)
+}
+
+func TestMCTSStrategy(t *testing.T) {
+	g := pkg.NewGame(pkg.GameOptions{Seed: 1})
+	g.DealHands([6]pkg.Cards{
+		pkg.CardStrToCards("S3 H3"),
+		pkg.CardStrToCards("S5 S6 D9 HK CA"),
+		pkg.CardStrToCards("C4 D4 S8 HQ SA"),
+		pkg.CardStrToCards("S4 H6 C9 DJ H2"),
+		pkg.CardStrToCards("H5 C6 D8 SJ D2"),
+		pkg.CardStrToCards("C5 D6 H9 CQ 大"),
+	})
+	g.SetLeader(0)
+	s := pkg.NewMCTSStrategy()
+	s.Iterations = 60
+	s.Budget = 0
+	shot := s.NextShot(g.View(0), g.CurrentShot())
+	require.Equal(t, "S3 H3", shot.String())
+	pair := pkg.Shot{Cards: pkg.CardStrToCards("S3 H3"), Type: pkg.ShotTypeTwo, Team: 1}
+	single := pkg.Shot{Cards: pkg.CardStrToCards("S3"), Type: pkg.ShotTypeOne, Team: 1}
+	// going out wins more often than keeping a card
+	rates := s.WinRates(g.View(0), g.CurrentShot(), []pkg.Shot{pair, single})
+	require.Less(t, rates[1], rates[0])
+
+	// the zero value runs the default iterations with normal rollouts
+	shot = (&pkg.MCTSStrategy{}).NextShot(g.View(0), g.CurrentShot())
+	require.Equal(t, "S3 H3", shot.String())
+
+	g = pkg.NewGame(pkg.GameOptions{Seed: 6})
+	g.Start()
+	s.Iterations = 20
+	for i := 0; i < 3; i++ {
+		cur := g.CurrentPlayer()
+		view := g.View(cur)
+		shot := s.NextShot(view, g.CurrentShot())
+		require.Equal(t, shot, s.NextShot(view, g.CurrentShot()))
+		require.NoError(t, g.Apply(cur, shot))
+	}
+}
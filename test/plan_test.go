@@ -0,0 +1,79 @@
+package test
+
+import (
+	"sort"
+	"testing"
+
+	"CardGame3V3Go/pkg"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRules_PlanHand(t *testing.T) {
+	rules := pkg.DefaultRules()
+	for _, c := range []struct {
+		hand string
+		plan []string
+	}{
+		{"S3 H4 C5 D6 S7 S9 H9 C9 D0 D0", []string{"S3 H4 C5 D6 S7", "S9 H9 C9 D0 D0"}},
+		{"S3 H3 C3 S4 H5 C6 D7 D7", []string{"D7", "H3 C3", "S3 S4 H5 C6 D7"}},
+		{"H3 S4 H5 H7 H9 HJ", []string{"S4", "H3 H5 H7 H9 HJ"}},
+		{"S3 H3 C3 D3 SK", []string{"S3 H3 C3 D3 SK"}},
+		{"SK 小 小 大 大", []string{"SK", "小 小 大 大"}},
+		{"S3 S3 H3 H3 C3 C3 S8", []string{"S8", "S3 S3 H3 H3 C3 C3"}},
+		{"S3 H3 S5 S7 S9 SJ", []string{"H3", "S3 S5 S7 S9 SJ"}},
+		{"S3 S5 H5 H5 S7 S9 SJ", []string{"H5 H5", "S3 S5 S7 S9 SJ"}},
+	} {
+		plan := rules.PlanHand(pkg.CardStrToCards(c.hand))
+		require.Equal(t, c.plan, shotStrings(plan), c.hand)
+		var cards pkg.Cards
+		for _, shot := range plan {
+			cards = append(cards, shot.Cards...)
+		}
+		sort.Sort(pkg.CardSorter(cards))
+		require.Equal(t, c.hand, cards.String())
+	}
+
+	// the 2 ranks lowest and still makes a flush
+	rules.TwoHigh = false
+	plan := rules.PlanHand(pkg.CardStrToCards("S2 S3 S5 S7 S9 HK"))
+	require.Equal(t, []string{"HK", "S2 S3 S5 S7 S9"}, shotStrings(plan))
+	plan = rules.PlanHand(pkg.CardStrToCards("S2 H3 C4 D5 S6 SA"))
+	require.Equal(t, []string{"SA", "S2 H3 C4 D5 S6"}, shotStrings(plan))
+	rules.TwoHigh = true
+
+	g := pkg.NewGame(pkg.GameOptions{Seed: 3})
+	g.Start()
+	for i := range g.Players {
+		plan := rules.PlanHand(g.Players[i].Cards)
+		require.Less(t, len(plan), len(g.Players[i].Cards)/2)
+	}
+}
+
+func TestPlanStrategy(t *testing.T) {
+	s := pkg.PlanStrategy{}
+	view := pkg.TableView{
+		Rules: pkg.DefaultRules(),
+		Team:  1,
+		Hand:  pkg.CardStrToCards("S3 H4 C5 D6 S7 S9 H9 C9 D0 D0 SK"),
+	}
+	require.Equal(t, "SK", s.NextShot(view, pkg.Shot{}).String())
+	single := func(str string, team uint32) pkg.Shot {
+		return pkg.Shot{Cards: pkg.CardStrToCards(str), Type: pkg.ShotTypeOne, Team: team}
+	}
+	require.Equal(t, "SK", s.NextShot(view, single("S8", 2)).String())
+	// a pair of tens costs one more shot, a pair of nines two
+	pair := pkg.Shot{Cards: pkg.CardStrToCards("S8 H8"), Type: pkg.ShotTypeTwo, Team: 2}
+	require.Equal(t, "D0 D0", s.NextShot(view, pair).String())
+	require.Equal(t, "pass", s.NextShot(view, single("SA", 2)).String())
+	require.Equal(t, "pass", s.NextShot(view, single("SK", 1)).String())
+
+	// the kept plan leads its shots one after another
+	for _, want := range view.Rules.PlanHand(view.Hand) {
+		shot := s.NextShot(view, pkg.Shot{})
+		require.Equal(t, want.String(), shot.String())
+		for _, card := range shot.Cards {
+			view.Hand = view.Hand.Delete(card)
+		}
+	}
+	require.Empty(t, view.Hand)
+}
@@ -0,0 +1,117 @@
+package test
+
+import (
+	"net"
+	"testing"
+
+	"CardGame3V3Go/pkg"
+	"CardGame3V3Go/pkg/server"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMessageConn(t *testing.T) {
+	a, b := net.Pipe()
+	defer a.Close()
+	defer b.Close()
+	turn := pkg.Turn{
+		Hand:   pkg.CardStrToCards("S3 H3 大"),
+		Shot:   pkg.Shot{Cards: pkg.CardStrToCards("D0"), Type: pkg.ShotTypeOne, Team: 2},
+		Friend: true,
+	}
+	go pkg.NewMessageConn(a).Send(pkg.MsgTurn, turn)
+	msg, err := pkg.NewMessageConn(b).Receive()
+	require.NoError(t, err)
+	require.Equal(t, pkg.MsgTurn, msg.Type)
+	require.JSONEq(t, `{"hand":"S3 H3 大","shot":{"cards":"D0","type":1,"team":2},"friend":true,"cardCounts":[0,0,0,0,0,0]}`, string(msg.Data))
+	var got pkg.Turn
+	require.NoError(t, msg.Decode(&got))
+	require.Equal(t, turn, got)
+}
+
+// expectMessage receives the next message, which must be of type typ.
+func expectMessage(t *testing.T, mc *pkg.MessageConn, typ string, data interface{}) {
+	msg, err := mc.Receive()
+	require.NoError(t, err)
+	require.Equal(t, typ, msg.Type, string(msg.Data))
+	require.NoError(t, msg.Decode(data))
+}
+
+func TestServer_Protocol(t *testing.T) {
+	s := server.New(pkg.GameOptions{Seed: 4}, 0)
+	conn, serverConn := net.Pipe()
+	defer conn.Close()
+	go s.ServeConn(serverConn)
+	done := make(chan error, 1)
+	go func() {
+		done <- s.Run()
+	}()
+	mc := pkg.NewMessageConn(conn)
+	var e pkg.ErrorMsg
+
+	require.NoError(t, mc.Send(pkg.MsgStart, nil))
+	expectMessage(t, mc, pkg.MsgError, &e)
+	seat := 2
+	require.NoError(t, mc.Send(pkg.MsgJoin, pkg.Join{Name: "pipe", Seat: &seat}))
+	var seated pkg.Seated
+	expectMessage(t, mc, pkg.MsgSeated, &seated)
+	require.Equal(t, pkg.Seated{Seat: 2, Team: 1}, seated)
+	require.NoError(t, mc.Send(pkg.MsgPlay, pkg.Play{Cards: pkg.CardStrToCards("3")}))
+	expectMessage(t, mc, pkg.MsgError, &e)
+	require.Equal(t, "not your turn", e.Error)
+	require.NoError(t, mc.Send("shuffle", nil))
+	expectMessage(t, mc, pkg.MsgError, &e)
+
+	require.NoError(t, mc.Send(pkg.MsgStart, nil))
+	var deal pkg.Deal
+	expectMessage(t, mc, pkg.MsgDeal, &deal)
+	require.Equal(t, 2, deal.Seat)
+	require.Len(t, deal.Hand, 27)
+
+	var rejected, played bool
+	for {
+		msg, err := mc.Receive()
+		require.NoError(t, err)
+		switch msg.Type {
+		case pkg.MsgTurn:
+			var turn pkg.Turn
+			require.NoError(t, msg.Decode(&turn))
+			require.Equal(t, turn.Shot.Type != pkg.ShotTypePass && turn.Shot.Team == 1, turn.Friend)
+			require.Len(t, turn.Hand, turn.CardCounts[2])
+			if !rejected {
+				// a card of the deck that is not in the hand
+				var missing pkg.Card
+				for _, card := range deal.Rules.Deck() {
+					if !turn.Hand.Contains(card) {
+						missing = card
+						break
+					}
+				}
+				require.NoError(t, mc.Send(pkg.MsgPlay, pkg.Play{Cards: pkg.Cards{missing}}))
+				expectMessage(t, mc, pkg.MsgError, &e)
+				rejected = true
+			}
+			if turn.Shot.Type == pkg.ShotTypePass {
+				require.NoError(t, mc.Send(pkg.MsgPass, nil))
+				expectMessage(t, mc, pkg.MsgError, &e)
+				shots := pkg.LegalShots(turn.Hand, turn.Shot)
+				require.NoError(t, mc.Send(pkg.MsgPlay, pkg.Play{Cards: shots[0].Cards}))
+			} else {
+				require.NoError(t, mc.Send(pkg.MsgPass, nil))
+			}
+		case pkg.MsgPlayed:
+			var shot pkg.Played
+			require.NoError(t, msg.Decode(&shot))
+			played = played || shot.Seat == 2
+		case pkg.MsgHandOver:
+			var over pkg.HandOverMsg
+			require.NoError(t, msg.Decode(&over))
+			require.True(t, over.Over)
+			require.True(t, rejected)
+			require.True(t, played)
+			require.NoError(t, <-done)
+			return
+		case pkg.MsgError:
+			t.Fatalf("unexpected error %s", msg.Data)
+		}
+	}
+}
@@ -0,0 +1,59 @@
+package test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"CardGame3V3Go/pkg"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRatings(t *testing.T) {
+	r := pkg.NewRatings()
+	names := [6]string{"ann", "bob", "cat", "dan", "eve", ""}
+	teams := pkg.DefaultRules().Teams
+	result := pkg.HandResult{Winner: 1, Ranking: []int{0, 1, 2, 3, 4, 5}}
+	r.Record(names, teams, result)
+	require.Len(t, r.Players, 5)
+
+	sum := 0.0
+	for _, name := range names[:5] {
+		sum += r.Get(name).Rating - pkg.InitialRating
+	}
+	// ratings are zero-sum, the unnamed last seat lost all of its games
+	require.InDelta(t, r.K/2, sum, 1e-9)
+	require.True(t, r.Get("ann").Rating > r.Get("cat").Rating)
+	require.True(t, r.Get("cat").Rating > r.Get("eve").Rating)
+	require.True(t, r.Get("eve").Rating > pkg.InitialRating)
+	require.True(t, r.Get("bob").Rating < pkg.InitialRating)
+	require.Equal(t, 1, r.Get("ann").Wins)
+	require.Equal(t, 0, r.Get("bob").Wins)
+
+	// a player of several seats plays the hand once
+	self := pkg.NewRatings()
+	self.Record([6]string{"normal", "normal", "normal", "normal", "normal", "normal"}, teams, result)
+	require.Equal(t, 1, self.Get("normal").Hands)
+	require.Equal(t, 0, self.Get("normal").Wins)
+	require.InDelta(t, pkg.InitialRating, self.Get("normal").Rating, 1e-9)
+	self.Record([6]string{"normal", "coop", "normal", "coop", "normal", "coop"}, teams, result)
+	require.Equal(t, 2, self.Get("normal").Hands)
+	require.Equal(t, 1, self.Get("normal").Wins)
+	require.Equal(t, 1, self.Get("coop").Hands)
+
+	board := r.Leaderboard()
+	require.Equal(t, []string{"ann", "cat", "eve", "bob", "dan"}, []string{board[0].Name, board[1].Name, board[2].Name, board[3].Name, board[4].Name})
+
+	dir, err := ioutil.TempDir("", "ratings")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "ratings.json")
+	empty, err := pkg.LoadRatings(path)
+	require.NoError(t, err)
+	require.Empty(t, empty.Players)
+	require.NoError(t, r.Save(path))
+	loaded, err := pkg.LoadRatings(path)
+	require.NoError(t, err)
+	require.Equal(t, r, loaded)
+}
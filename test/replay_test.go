@@ -0,0 +1,63 @@
+package test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"CardGame3V3Go/pkg"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReplay_RecordAndPlay(t *testing.T) {
+	var buf bytes.Buffer
+	m := pkg.NewMatch(pkg.GameOptions{Seed: 21}, 10)
+	recorder := pkg.RecordReplay(&m.Game, &buf, [6]string{"normal", "normal", "normal", "normal", "normal", "normal"})
+	for i := 0; i < 2; i++ {
+		_, err := m.PlayHand()
+		require.NoError(t, err)
+	}
+	require.NoError(t, recorder.Err())
+
+	replays, err := pkg.ReadReplays(&buf)
+	require.NoError(t, err)
+	require.Len(t, replays, 2)
+	for i, r := range replays {
+		require.Equal(t, pkg.ReplayVersion, r.Version)
+		require.Equal(t, int64(21), r.Seed)
+		require.Equal(t, "normal", r.Seats[0])
+		require.Equal(t, m.Hands[i].Turns, len(r.Moves))
+		g, err := r.Game(-1)
+		require.NoError(t, err)
+		result, err := g.Result()
+		require.NoError(t, err)
+		require.Equal(t, m.Hands[i].HandResult, result)
+
+		g, err = r.Game(0)
+		require.NoError(t, err)
+		for seat := range g.Players {
+			require.Equal(t, r.Hands[seat], pkg.Cards(g.Players[seat].Cards))
+		}
+		require.Equal(t, r.Leader, g.CurrentPlayer())
+	}
+
+	r := replays[0]
+	r.Moves = append([]pkg.ReplayMove(nil), r.Moves...)
+	r.Moves[3].Seat = (r.Moves[3].Seat + 1) % 6
+	_, err = r.Game(3)
+	require.NoError(t, err)
+	_, err = r.Game(4)
+	require.Error(t, err)
+}
+
+func TestReadReplays_Errors(t *testing.T) {
+	for _, file := range []string{
+		`{"type":"header","version":99}`,
+		`{"type":"move","turn":1,"seat":0,"shotType":0}`,
+		`{"type":"unknown"}`,
+		`{"type":"header","version":1,"hands":["S3 X9"]}`,
+	} {
+		_, err := pkg.ReadReplays(strings.NewReader(file))
+		require.Error(t, err, file)
+	}
+}
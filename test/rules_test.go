@@ -0,0 +1,160 @@
+package test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"CardGame3V3Go/pkg"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadRules(t *testing.T) {
+	dir, err := ioutil.TempDir("", "rules")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	jsonPath := filepath.Join(dir, "rules.json")
+	require.NoError(t, ioutil.WriteFile(jsonPath, []byte(`{"decks": 2, "twoHigh": false, "teams": [1, 1, 1, 2, 2, 2]}`), 0644))
+	rules, err := pkg.LoadRules(jsonPath)
+	require.NoError(t, err)
+	expected := pkg.DefaultRules()
+	expected.Decks = 2
+	expected.TwoHigh = false
+	expected.Teams = [6]uint32{1, 1, 1, 2, 2, 2}
+	require.Equal(t, expected, rules)
+
+	yamlPath := filepath.Join(dir, "rules.yaml")
+	require.NoError(t, ioutil.WriteFile(yamlPath, []byte("decks: 2\ntwoHigh: false\nteams: [1, 1, 1, 2, 2, 2]\n"), 0644))
+	rules, err = pkg.LoadRules(yamlPath)
+	require.NoError(t, err)
+	require.Equal(t, expected, rules)
+
+	for _, content := range []string{
+		`{"decks": 0}`,
+		`{"shotTypes": [2, 3]}`,
+		`{"shotTypes": [1, 4]}`,
+		`{"teams": [1, 2, 1, 2, 1, 1]}`,
+		`{"decks": "three"}`,
+		`{"bombs": {"minOfAKind": 3}}`,
+		`{"bombs": {"minJokers": 1}}`,
+		`{"deck": 2}`,
+	} {
+		require.NoError(t, ioutil.WriteFile(jsonPath, []byte(content), 0644))
+		_, err = pkg.LoadRules(jsonPath)
+		require.Error(t, err, content)
+	}
+}
+
+func TestRules_Variants(t *testing.T) {
+	rules := pkg.DefaultRules()
+	single := func(str string) pkg.Shot {
+		return pkg.Shot{Cards: pkg.CardStrToCards(str), Type: pkg.ShotTypeOne}
+	}
+	five := func(str string) pkg.Shot {
+		return pkg.Shot{Cards: pkg.CardStrToCards(str), Type: pkg.ShotTypeFive}
+	}
+	require.NoError(t, rules.Beats(single("S2"), single("SA")))
+	require.Error(t, rules.Beats(five("S3 H3 C3 D3 S3"), single("SA")))
+	require.NoError(t, rules.Beats(five("大 大 S7 H7 C7"), five("S3 H4 C5 D6 S7")))
+
+	rules.TwoHigh = false
+	require.Error(t, rules.Beats(single("S2"), single("S3")))
+	require.NoError(t, rules.Beats(five("S3 H4 C5 D6 S7"), five("S2 H3 C4 D5 S6")))
+	_, err := rules.ShotTypeOf(pkg.CardStrToCards("S0 HJ CQ DK S2"))
+	require.Error(t, err)
+
+	rules.FiveOfAKindBeatsAll = true
+	require.NoError(t, rules.Beats(five("S3 H3 C3 D3 S3"), single("SA")))
+	require.Error(t, rules.Beats(five("S3 H4 C5 D6 S7"), single("SA")))
+
+	rules.FiveBeatsOnlyFive = false
+	require.NoError(t, rules.Beats(five("S3 H4 C5 D6 S7"), single("SA")))
+
+	rules.JokersInFive = false
+	require.Error(t, rules.Beats(five("大 大 S7 H7 C7"), five("S3 H4 C5 D6 S7")))
+
+	rules.ShotTypes = []pkg.ShotType{pkg.ShotTypeOne, pkg.ShotTypeTwo}
+	shots := rules.LegalShots(pkg.CardStrToCards("S3 H3 C3 S4 S5 S6 S7"), pkg.Shot{})
+	require.Equal(t, []string{"S3", "H3", "C3", "S4", "S5", "S6", "S7", "S3 H3", "S3 C3", "H3 C3", "S3 S4 S5 S6 S7"}, shotStrings(shots))
+	require.Equal(t, pkg.ShotTypeBomb, shots[len(shots)-1].Type)
+}
+
+func TestGame_Rules(t *testing.T) {
+	rules := pkg.DefaultRules()
+	rules.Decks = 1
+	rules.TwoHigh = false
+	rules.Teams = [6]uint32{1, 1, 1, 2, 2, 2}
+	g := pkg.NewGame(pkg.GameOptions{Seed: 5, Rules: &rules})
+	g.Start()
+	for i := range g.Players {
+		require.Len(t, g.Players[i].Cards, 9)
+		require.Equal(t, rules.Teams[i], g.Players[i].Team)
+	}
+	_, err := g.Play()
+	require.NoError(t, err)
+	require.Panics(t, func() { pkg.NewGame(pkg.GameOptions{Rules: &pkg.Rules{}}) })
+	winner := g.Winner()
+	for i := range g.Players {
+		if g.Players[i].Team == winner {
+			require.True(t, g.Players[i].IsFinished())
+		}
+	}
+}
+
+func TestRules_Bombs(t *testing.T) {
+	rules := pkg.DefaultRules()
+	shot := func(str string, shotType pkg.ShotType) pkg.Shot {
+		return pkg.Shot{Cards: pkg.CardStrToCards(str), Type: shotType}
+	}
+	bomb := func(str string) pkg.Shot {
+		return shot(str, pkg.ShotTypeBomb)
+	}
+	require.NoError(t, rules.Beats(bomb("S3 H3 C3 D3"), shot("S2", pkg.ShotTypeOne)))
+	require.NoError(t, rules.Beats(bomb("S3 H3 C3 D3"), shot("小 小", pkg.ShotTypeTwo)))
+	require.NoError(t, rules.Beats(bomb("S3 S4 S5 S6 S7"), shot("S3 H4 C5 D6 S7", pkg.ShotTypeFive)))
+	require.Error(t, rules.Beats(bomb("S3 H4 C5 D6 S7"), shot("S2", pkg.ShotTypeOne)))
+	require.Error(t, rules.Beats(shot("小 小 小", pkg.ShotTypeThree), bomb("S3 H3 C3 D3")))
+	require.Error(t, rules.Beats(shot("S3 S3 S3 S3 S3", pkg.ShotTypeFive), bomb("S4 H4 C4 D4")))
+	// a straight flush played as a five-card shot is still a 5-card bomb
+	flush := shot("S3 S4 S5 S6 S7", pkg.ShotTypeFive)
+	require.Error(t, rules.Beats(bomb("S2 H2 C2 D2"), flush))
+	require.NoError(t, rules.Beats(bomb("S2 H2 C2 D2 D2"), flush))
+	require.NoError(t, rules.Beats(shot("S4 S5 S6 S7 S8", pkg.ShotTypeFive), flush))
+
+	for _, c := range []struct {
+		a, b string
+		cmp  int
+	}{
+		{"S3 H3 C3 D3", "S2 H2 C2 D2", -1},
+		{"S3 H3 C3 D3 D3", "S2 H2 C2 D2", 1},
+		{"S3 S4 S5 S6 S7", "S2 H2 C2 D2 D2", -1},
+		{"S3 S4 S5 S6 S7", "S2 H2 C2 D2", 1},
+		{"小 小 大 大", "S2 H2 C2 D2", 1},
+		{"小 小 小 大", "小 小 大 大", -1},
+		{"S3 S4 S5 S6 S7", "H3 H4 H5 H6 H7", 0},
+	} {
+		cmp, err := rules.Compare(pkg.CardStrToCards(c.a), pkg.CardStrToCards(c.b))
+		require.NoError(t, err)
+		require.Equal(t, c.cmp, cmp, "%s vs %s", c.a, c.b)
+	}
+
+	cur := shot("S2", pkg.ShotTypeOne)
+	require.True(t, cur.CheckLarger(pkg.CardStrToCards("S3 H3 C3 D3")))
+	played, err := rules.ShotFor(pkg.CardStrToCards("S3 H3 C3 D3 D3"), cur)
+	require.NoError(t, err)
+	require.Equal(t, pkg.ShotTypeBomb, played.Type)
+	played, err = rules.ShotFor(pkg.CardStrToCards("S3 H3 C3 D3 D3"), shot("S3 H4 C5 D6 S7", pkg.ShotTypeFive))
+	require.NoError(t, err)
+	require.Equal(t, pkg.ShotTypeFive, played.Type)
+
+	shots := rules.LegalShots(pkg.CardStrToCards("S3 H3 C3 D3 小 小 大 大 S9"), cur)
+	require.Equal(t, []string{"小", "大", "S3 H3 C3 D3", "小 小 大 大"}, shotStrings(shots))
+
+	rules.Bombs = pkg.BombRules{}
+	_, err = rules.ShotFor(pkg.CardStrToCards("S3 H3 C3 D3"), cur)
+	require.Error(t, err)
+	shots = rules.LegalShots(pkg.CardStrToCards("S3 H3 C3 D3 小 小 大 大 S9"), cur)
+	require.Equal(t, []string{"小", "大"}, shotStrings(shots))
+}
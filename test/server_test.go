@@ -0,0 +1,146 @@
+package test
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"CardGame3V3Go/pkg"
+	"CardGame3V3Go/pkg/server"
+	"github.com/stretchr/testify/require"
+)
+
+// joinTable joins a seat and returns the connection with the seat.
+func joinTable(t *testing.T, addr string, join pkg.Join) (*pkg.MessageConn, pkg.Seated) {
+	conn, err := net.Dial("tcp", addr)
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+	mc := pkg.NewMessageConn(conn)
+	require.NoError(t, mc.Send(pkg.MsgJoin, join))
+	msg, err := mc.Receive()
+	require.NoError(t, err)
+	require.Equal(t, pkg.MsgSeated, msg.Type, string(msg.Data))
+	var seated pkg.Seated
+	require.NoError(t, msg.Decode(&seated))
+	return mc, seated
+}
+
+// playTable plays the smallest legal shot on every turn until the match is
+// over and returns the messages received by type.
+func playTable(mc *pkg.MessageConn) (received map[string][]pkg.Message, err error) {
+	received = make(map[string][]pkg.Message)
+	for {
+		msg, err := mc.Receive()
+		if err != nil {
+			return received, err
+		}
+		received[msg.Type] = append(received[msg.Type], msg)
+		switch msg.Type {
+		case pkg.MsgTurn:
+			var turn pkg.Turn
+			if err = msg.Decode(&turn); err != nil {
+				return received, err
+			}
+			if turn.Shot.Type == pkg.ShotTypePass {
+				shots := pkg.LegalShots(turn.Hand, turn.Shot)
+				err = mc.Send(pkg.MsgPlay, pkg.Play{Cards: shots[0].Cards})
+			} else {
+				err = mc.Send(pkg.MsgPass, nil)
+			}
+			if err != nil {
+				return received, err
+			}
+		case pkg.MsgHandOver:
+			var over pkg.HandOverMsg
+			if err = msg.Decode(&over); err != nil || over.Over {
+				return received, err
+			}
+		}
+	}
+}
+
+func TestServer_Match(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer l.Close()
+	s := server.New(pkg.GameOptions{Seed: 9}, 3)
+	done := make(chan error)
+	go func() {
+		done <- s.Serve(l)
+	}()
+
+	addr := l.Addr().String()
+	a, seatA := joinTable(t, addr, pkg.Join{Name: "a"})
+	require.Equal(t, 0, seatA.Seat)
+	b, seatB := joinTable(t, addr, pkg.Join{Name: "b", Team: 2})
+	require.Equal(t, 1, seatB.Seat)
+	require.Equal(t, uint32(2), seatB.Team)
+
+	taken := 1
+	require.NoError(t, a.Send(pkg.MsgJoin, pkg.Join{Name: "again"}))
+	require.NoError(t, a.Send(pkg.MsgPlay, pkg.Play{Cards: pkg.CardStrToCards("3")}))
+	for i := 0; i < 2; i++ {
+		msg, err := a.Receive()
+		require.NoError(t, err)
+		require.Equal(t, pkg.MsgError, msg.Type)
+	}
+	conn, err := net.Dial("tcp", addr)
+	require.NoError(t, err)
+	c := pkg.NewMessageConn(conn)
+	require.NoError(t, c.Send(pkg.MsgJoin, pkg.Join{Seat: &taken}))
+	msg, err := c.Receive()
+	require.NoError(t, err)
+	require.Equal(t, pkg.MsgError, msg.Type)
+	conn.Close()
+
+	require.NoError(t, a.Send(pkg.MsgStart, nil))
+	results := make(chan map[string][]pkg.Message, 2)
+	errs := make(chan error, 2)
+	for _, mc := range []*pkg.MessageConn{a, b} {
+		go func(mc *pkg.MessageConn) {
+			received, err := playTable(mc)
+			results <- received
+			errs <- err
+		}(mc)
+	}
+	require.NoError(t, <-done)
+	for i := 0; i < 2; i++ {
+		received := <-results
+		require.NoError(t, <-errs)
+		require.NotEmpty(t, received[pkg.MsgTurn])
+		require.Empty(t, received[pkg.MsgError])
+		require.Len(t, received[pkg.MsgDeal], len(received[pkg.MsgHandOver]))
+		var deal pkg.Deal
+		require.NoError(t, received[pkg.MsgDeal][0].Decode(&deal))
+		require.Len(t, deal.Hand, 27)
+		require.Equal(t, [6]string{"a", "b", "normal", "normal", "normal", "normal"}, deal.Names)
+
+		var over pkg.HandOverMsg
+		hands := received[pkg.MsgHandOver]
+		require.NoError(t, hands[len(hands)-1].Decode(&over))
+		require.True(t, over.Over)
+		require.GreaterOrEqual(t, over.Scores[over.Result.Winner], 3)
+	}
+}
+
+func TestServer_TurnTime(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer l.Close()
+	s := server.New(pkg.GameOptions{Seed: 9}, 0)
+	s.TurnTime = 50 * time.Millisecond
+	done := make(chan error)
+	go func() {
+		done <- s.Serve(l)
+	}()
+
+	// the client never answers, the AI takes its seat
+	a, _ := joinTable(t, l.Addr().String(), pkg.Join{Name: "a"})
+	require.NoError(t, a.Send(pkg.MsgStart, nil))
+	select {
+	case err := <-done:
+		require.NoError(t, err)
+	case <-time.After(10 * time.Second):
+		t.Fatal("the table waits for the client")
+	}
+}
@@ -0,0 +1,46 @@
+package test
+
+import (
+	"testing"
+
+	"CardGame3V3Go/pkg"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSimulate(t *testing.T) {
+	opts := pkg.SimOptions{
+		Games:   20,
+		Seed:    7,
+		Seats:   [6]string{"normal", "coop", "normal", "coop", "normal", "coop"},
+		Workers: 1,
+	}
+	serial, err := pkg.Simulate(opts)
+	require.NoError(t, err)
+	opts.Workers = 4
+	parallel, err := pkg.Simulate(opts)
+	require.NoError(t, err)
+	require.Equal(t, serial, parallel)
+
+	require.Equal(t, 20, serial.Games)
+	require.InDelta(t, 1, serial.WinRate[1].Mean+serial.WinRate[2].Mean, 1e-9)
+	places := 0.0
+	for _, place := range serial.Place {
+		places += place.Mean
+		require.True(t, place.StdErr > 0)
+	}
+	require.InDelta(t, 21, places, 1e-9)
+	lo, hi := serial.Turns.CI95()
+	require.True(t, lo < serial.Turns.Mean && serial.Turns.Mean < hi)
+
+	opts.Games = -1
+	_, err = pkg.Simulate(opts)
+	require.Error(t, err)
+	opts.Games = 20
+	opts.Rules = &pkg.Rules{}
+	_, err = pkg.Simulate(opts)
+	require.Error(t, err)
+	opts.Rules = nil
+	opts.Seats[2] = "nobody"
+	_, err = pkg.Simulate(opts)
+	require.Error(t, err)
+}
@@ -0,0 +1,63 @@
+package test
+
+import (
+	"testing"
+
+	"CardGame3V3Go/pkg"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTournament(t *testing.T) {
+	// the same players on both sides win every deal once in each seating
+	result, err := pkg.Tournament(pkg.TournamentOptions{
+		Entries: []string{"normal", "normal+normal+normal"},
+		Deals:   4,
+		Seed:    3,
+	})
+	require.NoError(t, err)
+	require.Len(t, result.Pairings, 1)
+	p := result.Pairings[0]
+	require.Equal(t, 8, p.Hands)
+	require.Equal(t, 4, p.WinsA)
+	require.Equal(t, 4, p.WinsB)
+	require.Equal(t, p.PointsA, p.PointsB)
+	require.Zero(t, p.SweepsA+p.SweepsB)
+
+	opts := pkg.TournamentOptions{
+		Entries: []string{"normal", "coop", "planner+normal+coop"},
+		Deals:   3,
+		Seed:    5,
+		Workers: 1,
+	}
+	result, err = pkg.Tournament(opts)
+	require.NoError(t, err)
+	require.Len(t, result.Pairings, 3)
+	require.Len(t, result.Standings, 3)
+	for _, s := range result.Standings {
+		require.Equal(t, 12, s.Hands)
+	}
+	for i := 1; i < len(result.Standings); i++ {
+		require.True(t, result.Standings[i-1].WinRate.Mean >= result.Standings[i].WinRate.Mean)
+	}
+	for _, p := range result.Pairings {
+		require.Equal(t, 6, p.WinsA+p.WinsB)
+		require.True(t, p.SweepsA+p.SweepsB <= 3)
+		require.InDelta(t, float64(p.WinsA)/6, p.WinRateA.Mean, 1e-9)
+	}
+	opts.Workers = 3
+	parallel, err := pkg.Tournament(opts)
+	require.NoError(t, err)
+	require.Equal(t, result, parallel)
+
+	for _, entries := range [][]string{
+		{"normal"},
+		{"normal", "normal"},
+		{"normal", "coop+normal"},
+		{"normal", "nobody"},
+	} {
+		_, err = pkg.Tournament(pkg.TournamentOptions{Entries: entries, Deals: 1})
+		require.Error(t, err, "%v", entries)
+	}
+	_, err = pkg.Tournament(pkg.TournamentOptions{Entries: []string{"normal", "coop"}})
+	require.Error(t, err)
+}
@@ -0,0 +1,120 @@
+package test
+
+import (
+	"testing"
+
+	"CardGame3V3Go/pkg"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCardTracker_Game(t *testing.T) {
+	g := pkg.NewGame(pkg.GameOptions{Seed: 12})
+	followed := pkg.NewCardTracker(g.Rules)
+	g.Subscribe(followed)
+	g.Start()
+	for g.Turns() < 60 {
+		cur := g.CurrentPlayer()
+		require.NoError(t, g.Apply(cur, g.Players[cur].NextShot(g.View(cur), g.CurrentShot())))
+	}
+
+	seat := g.CurrentPlayer()
+	view := g.View(seat)
+	tracker := view.Tracker()
+	held := 0
+	for i, n := range tracker.Holders() {
+		if i != seat {
+			require.Equal(t, view.CardCounts[i], n)
+			held += n
+		}
+	}
+	require.Len(t, tracker.UnseenCards(), held)
+	require.Equal(t, view.CardCounts, followed.Holders())
+
+	for _, card := range pkg.CardStrToCards("S3 H3 C3 D3 3 D0 0 2 小 大") {
+		played, inHand := 0, 0
+		for i := range view.Played {
+			played += len(cardsLike(view.Played[i], card))
+		}
+		inHand = len(cardsLike(view.Hand, card))
+		all := 3
+		if card.Color == "" && !card.IsJoker() {
+			all = 12
+		}
+		require.Equal(t, all-played, tracker.Remaining(card), card.String())
+		require.Equal(t, all-played, followed.Remaining(card), card.String())
+		require.Equal(t, all-played-inHand, tracker.Unseen(card), card.String())
+	}
+}
+
+// cardsLike returns the cards of the card's number and, if it has one, its
+// color.
+func cardsLike(cards pkg.Cards, card pkg.Card) (like pkg.Cards) {
+	for _, c := range cards {
+		if c.Num == card.Num && (card.Color == "" || c.Color == card.Color) {
+			like = append(like, c)
+		}
+	}
+	return
+}
+
+// endgameView returns a view of seat 0 holding hand where only unseen is
+// still out, held by the other seats as counts says.
+func endgameView(decks int, hand, unseen string, counts [6]int) pkg.TableView {
+	rules := pkg.DefaultRules()
+	rules.Decks = decks
+	view := pkg.TableView{
+		Rules:      rules,
+		Hand:       pkg.CardStrToCards(hand),
+		CardCounts: counts,
+	}
+	played := rules.Deck()
+	for _, card := range append(view.Hand.Copy(), pkg.CardStrToCards(unseen)...) {
+		played = played.Delete(card)
+	}
+	view.Played[1] = played
+	return view
+}
+
+func TestCardTracker_Unbeatable(t *testing.T) {
+	twos := pkg.Shot{Cards: pkg.CardStrToCards("S2 H2"), Type: pkg.ShotTypeTwo}
+	three := pkg.Shot{Cards: pkg.CardStrToCards("S3"), Type: pkg.ShotTypeOne}
+	flush := pkg.Shot{Cards: pkg.CardStrToCards("DA DK DQ DJ D9"), Type: pkg.ShotTypeFive}
+	lowFlush := pkg.Shot{Cards: pkg.CardStrToCards("H3 H5 H7 H9 HK"), Type: pkg.ShotTypeFive}
+	cases := []struct {
+		decks  int
+		unseen string
+		counts [6]int
+		shot   pkg.Shot
+		want   bool
+	}{
+		{1, "C2 小 大 D5 D6", [6]int{3, 2, 2, 1}, twos, true},
+		{1, "C2 小 大 D5 D6", [6]int{3, 2, 2, 1}, three, false},
+		{2, "C2 D2 小 小 D6", [6]int{3, 2, 2, 1}, twos, false},
+		{2, "C2 D2 大 D6 H7", [6]int{3, 2, 2, 1}, twos, true},
+		{1, "S4 H4 C4 D4 D6", [6]int{3, 2, 2, 1}, twos, true},
+		{1, "S4 H4 C4 D4 D6", [6]int{3, 4, 0, 1}, twos, false},
+		{1, "S4 S5 S6 S7 S8", [6]int{3, 5}, twos, false},
+		{1, "S4 S5 S6 S7 H8", [6]int{3, 5}, twos, true},
+		{2, "SA SA SK SQ SJ", [6]int{3, 5}, flush, false},
+		{2, "SA SA SK SQ SJ", [6]int{3, 5}, lowFlush, false},
+		{2, "SA SA SK SQ H9", [6]int{3, 5}, lowFlush, true},
+	}
+	for _, c := range cases {
+		view := endgameView(c.decks, "S2 H2 S3", c.unseen, c.counts)
+		tracker := view.Tracker()
+		require.Len(t, tracker.UnseenCards(), 5)
+		require.Equal(t, c.want, tracker.Unbeatable(c.shot), "%s on %s", c.unseen, c.shot)
+	}
+
+	// a joker is the odd card of a four of a kind, when bombs do not decide
+	view := endgameView(2, "S2 H2 S3", "S4 H4 C4 D4 大", [6]int{3, 5})
+	view.Rules.Bombs.MinOfAKind = 0
+	four := pkg.Shot{Cards: pkg.CardStrToCards("H3 C3 D3 D3 S5"), Type: pkg.ShotTypeFive}
+	require.False(t, view.Tracker().Unbeatable(four))
+
+	view = endgameView(1, "S2 H2 S3", "S4 H4 C4 D4 D6", [6]int{3, 4, 0, 1})
+	tracker := view.Tracker()
+	require.Equal(t, 4, tracker.MaxHeld(1, pkg.Card{Num: 4}))
+	require.Equal(t, 1, tracker.MaxHeld(3, pkg.Card{Num: 4}))
+	require.Equal(t, 0, tracker.MaxHeld(2, pkg.Card{Num: 4}))
+}
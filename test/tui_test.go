@@ -0,0 +1,81 @@
+package test
+
+import (
+	"bufio"
+	"bytes"
+	"strings"
+	"testing"
+
+	"CardGame3V3Go/pkg"
+	"CardGame3V3Go/pkg/tui"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadKey(t *testing.T) {
+	r := bufio.NewReader(strings.NewReader("\x1b[D\x1b[Ch \npx"))
+	for _, want := range []tui.Key{tui.KeyLeft, tui.KeyRight, tui.KeyLeft, tui.KeyToggle, tui.KeyPlay, tui.KeyPass, tui.KeyNone} {
+		key, err := tui.ReadKey(r)
+		require.NoError(t, err)
+		require.Equal(t, want, key)
+	}
+	_, err := tui.ReadKey(r)
+	require.Error(t, err)
+}
+
+func TestSelector(t *testing.T) {
+	view := pkg.TableView{
+		Rules: pkg.DefaultRules(),
+		Team:  1,
+		Hand:  pkg.CardStrToCards("S2 S3 H3 D7 C7 C7 大"),
+	}
+	pair := pkg.Shot{Cards: pkg.CardStrToCards("H5 D5"), Type: pkg.ShotTypeTwo, Team: 2}
+	sel := tui.NewSelector(view, pair)
+	require.Equal(t, "S3 H3 C7 C7 D7 S2 大", sel.Hand.String())
+	// the threes are too small and the 2 and the joker are no pairs
+	var playable []string
+	for i := range sel.Hand {
+		if sel.Playable(i) {
+			playable = append(playable, sel.Hand[i].String())
+		}
+	}
+	require.Equal(t, []string{"C7", "C7", "D7"}, playable)
+
+	sel.Move(-1)
+	require.Equal(t, 6, sel.Cursor)
+	sel.Move(-4)
+	sel.PickNumber(true)
+	require.Equal(t, "C7 C7 D7", sel.Cards().String())
+	_, err := sel.Shot()
+	require.Error(t, err)
+	sel.Toggle()
+	shot, err := sel.Shot()
+	require.NoError(t, err)
+	require.Equal(t, pkg.Shot{Cards: pkg.CardStrToCards("C7 D7"), Type: pkg.ShotTypeTwo, Team: 1}, shot)
+	sel.Clear()
+	require.Empty(t, sel.Cards())
+}
+
+func TestTUIStrategy(t *testing.T) {
+	g := pkg.NewGame(pkg.GameOptions{Seed: 2})
+	var out bytes.Buffer
+	// pass is refused when leading, then the lowest card is played
+	s := tui.NewStrategy(strings.NewReader("p\n \n"), &out)
+	g.Subscribe(s)
+	g.Start()
+	seat := g.CurrentPlayer()
+	shot := s.NextShot(g.View(seat), g.CurrentShot())
+	require.Len(t, shot.Cards, 1)
+	require.Equal(t, g.View(seat).Hand[0], shot.Cards[0])
+	require.NoError(t, g.Apply(seat, shot))
+	screen := out.String()
+	require.Contains(t, screen, "You lead this round and cannot pass!")
+	require.Contains(t, screen, "Nothing picked")
+	require.Contains(t, screen, "ready to play")
+
+	out.Reset()
+	next := g.CurrentPlayer()
+	s.In = bufio.NewReader(strings.NewReader("p"))
+	require.Equal(t, pkg.ShotTypePass, s.NextShot(g.View(next), g.CurrentShot()).Type)
+	require.Contains(t, out.String(), "Shot to beat")
+	require.Contains(t, out.String(), "(you)")
+}